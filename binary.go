@@ -0,0 +1,147 @@
+package si
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Binary encoding versions, stored in the first byte of an encoded quantity.
+const (
+	binaryVersionFixed  = 1
+	binaryVersionVarint = 2
+)
+
+const (
+	// BinaryQuantitySize is the size in bytes of a quantity encoded by [Quantity.AppendBinary].
+	BinaryQuantitySize = 1 + 8 + 1 + 7
+	// MaxVarintQuantitySize is the maximum size in bytes of a quantity encoded by [Quantity.AppendVarint].
+	MaxVarintQuantitySize = 1 + binary.MaxVarintLen64 + 1 + 1 + 7
+)
+
+var (
+	errBinaryVersion  = errors.New("unsupported binary quantity version")
+	errBinaryTrailing = errors.New("unexpected data after binary quantity")
+)
+
+// AppendBinary appends the fixed size binary encoding of q to b. The encoding is [BinaryQuantitySize] bytes long:
+//
+//	byte 0: version, 1 for the fixed size encoding.
+//	bytes 1-8: fixed-point value as a little-endian int64.
+//	byte 9: base prefix exponent as an int8.
+//	bytes 10-16: dimension exponents as int8s in the order of [Dimension.Exponents].
+//
+// The returned error is always nil. AppendBinary does not allocate if b has enough capacity.
+func (q Quantity) AppendBinary(b []byte) ([]byte, error) {
+	var buf [BinaryQuantitySize]byte
+	buf[0] = binaryVersionFixed
+	binary.LittleEndian.PutUint64(buf[1:9], uint64(q.value))
+	buf[9] = byte(q.base)
+	for i, exp := range q.dim.dims {
+		buf[10+i] = byte(exp)
+	}
+	return append(b, buf[:]...), nil
+}
+
+// AppendVarint appends the variable size binary encoding of q to b, which is at most
+// [MaxVarintQuantitySize] bytes long and shorter for small values and simple dimensions:
+//
+//	byte 0: version, 2 for the variable size encoding.
+//	fixed-point value as a zig-zag varint, see [binary.PutVarint].
+//	base prefix exponent as an int8.
+//	bitmask of the non-zero dimension exponents, bit 0 being length.
+//	non-zero dimension exponents as int8s in the order of [Dimension.Exponents].
+func (q Quantity) AppendVarint(b []byte) []byte {
+	var buf [MaxVarintQuantitySize]byte
+	buf[0] = binaryVersionVarint
+	n := 1 + binary.PutVarint(buf[1:], q.value)
+	buf[n] = byte(q.base)
+	mask := n + 1
+	buf[mask] = 0
+	n += 2
+	for i, exp := range q.dim.dims {
+		if exp != 0 {
+			buf[mask] |= 1 << i
+			buf[n] = byte(exp)
+			n++
+		}
+	}
+	return append(b, buf[:n]...)
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler] with the fixed size encoding of [Quantity.AppendBinary].
+func (q Quantity) MarshalBinary() ([]byte, error) {
+	return q.AppendBinary(make([]byte, 0, BinaryQuantitySize))
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler]. It accepts both the fixed size
+// and the variable size encodings and data must hold exactly one encoded quantity.
+func (q *Quantity) UnmarshalBinary(data []byte) error {
+	nq, n, err := DecodeBinaryQuantity(data)
+	if err != nil {
+		return err
+	} else if n != len(data) {
+		return errBinaryTrailing
+	}
+	*q = nq
+	return nil
+}
+
+// DecodeBinaryQuantity decodes a quantity encoded by [Quantity.AppendBinary] or [Quantity.AppendVarint]
+// from the start of b. Returns the decoded quantity and the number of bytes read from b.
+// It returns [io.ErrUnexpectedEOF] if b is too short, [ErrInvalidPrefix] or [ErrDimOOB] if the decoded
+// quantity is not valid and an error if the encoding version is not supported.
+func DecodeBinaryQuantity(b []byte) (q Quantity, readBytes int, err error) {
+	if len(b) == 0 {
+		return Quantity{}, 0, io.ErrUnexpectedEOF
+	}
+	switch b[0] {
+	case binaryVersionFixed:
+		if len(b) < BinaryQuantitySize {
+			return Quantity{}, 0, io.ErrUnexpectedEOF
+		}
+		q.value = int64(binary.LittleEndian.Uint64(b[1:9]))
+		q.base = Prefix(b[9])
+		for i := range q.dim.dims {
+			q.dim.dims[i] = dimint(b[10+i])
+		}
+		readBytes = BinaryQuantitySize
+
+	case binaryVersionVarint:
+		var n int
+		q.value, n = binary.Varint(b[1:])
+		if n == 0 || len(b) < n+3 {
+			return Quantity{}, 0, io.ErrUnexpectedEOF
+		} else if n < 0 {
+			return Quantity{}, 0, ErrOverflow
+		}
+		readBytes = 1 + n
+		q.base = Prefix(b[readBytes])
+		mask := b[readBytes+1]
+		readBytes += 2
+		for i := range q.dim.dims {
+			if mask&(1<<i) == 0 {
+				continue
+			} else if readBytes == len(b) {
+				return Quantity{}, 0, io.ErrUnexpectedEOF
+			}
+			q.dim.dims[i] = dimint(b[readBytes])
+			readBytes++
+		}
+		if mask&(1<<len(q.dim.dims)) != 0 {
+			return Quantity{}, 0, ErrDimOOB
+		}
+
+	default:
+		return Quantity{}, 0, errBinaryVersion
+	}
+	if !q.base.IsValid() {
+		return Quantity{}, 0, ErrInvalidPrefix
+	}
+	for _, exp := range q.dim.dims {
+		if isDimOOB(int(exp)) {
+			return Quantity{}, 0, ErrDimOOB
+		}
+	}
+	return q, readBytes, nil
+}
@@ -0,0 +1,135 @@
+package si
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = Quantity{}
+	_ encoding.BinaryUnmarshaler = (*Quantity)(nil)
+)
+
+func TestQuantityBinary(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	var tests = []struct {
+		Q      Quantity
+		Fixed  []byte
+		Varint []byte
+	}{
+		0: {
+			Q:      Quantity{},
+			Fixed:  []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			Varint: []byte{2, 0, 0, 0},
+		},
+		1: {
+			Q:      Quantity{value: 3300, base: PrefixMicro, dim: current},
+			Fixed:  []byte{1, 0xe4, 0x0c, 0, 0, 0, 0, 0, 0, 0xfa, 0, 0, 0, 0, 1, 0, 0},
+			Varint: []byte{2, 0xc8, 0x33, 0xfa, 0b10000, 1},
+		},
+		2: {
+			Q:      Quantity{value: -1, base: PrefixKilo, dim: newdim([]int{2, 1, -3, 0, -2, 0, 0})},
+			Fixed:  []byte{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 3, 2, 1, 0xfd, 0, 0xfe, 0, 0},
+			Varint: []byte{2, 1, 3, 0b10111, 2, 1, 0xfd, 0xfe},
+		},
+	}
+	for i, test := range tests {
+		got, err := test.Q.MarshalBinary()
+		if err != nil || !bytes.Equal(got, test.Fixed) {
+			t.Errorf("case %d: want %x, got %x (%v)", i, test.Fixed, got, err)
+		}
+		if got = test.Q.AppendVarint(nil); !bytes.Equal(got, test.Varint) {
+			t.Errorf("case %d: want varint %x, got %x", i, test.Varint, got)
+		}
+		for _, data := range [][]byte{test.Fixed, test.Varint} {
+			var q Quantity
+			err = q.UnmarshalBinary(data)
+			if err != nil || q != test.Q {
+				t.Errorf("case %d: unmarshal %x got %v (%v)", i, data, q, err)
+			}
+		}
+	}
+}
+
+func TestQuantityBinaryLoop(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var buf []byte
+	var want []Quantity
+	for i := 0; i < 1000; i++ {
+		var q Quantity
+		q.value = rng.Int63() >> rng.Intn(63)
+		if rng.Intn(2) == 0 {
+			q.value = -q.value
+		}
+		q.base = Prefix(rng.Intn(61) - 30)
+		for !q.base.IsValid() {
+			q.base++
+		}
+		for j := range q.dim.dims {
+			if rng.Intn(3) == 0 {
+				q.dim.dims[j] = dimint(rng.Intn(255) - 127)
+			}
+		}
+		want = append(want, q)
+		if i%2 == 0 {
+			buf, _ = q.AppendBinary(buf)
+		} else {
+			buf = q.AppendVarint(buf)
+		}
+	}
+	for i := 0; len(buf) > 0; i++ {
+		q, n, err := DecodeBinaryQuantity(buf)
+		if err != nil || q != want[i] {
+			t.Fatalf("%d: want %v, got %v (%v)", i, want[i], q, err)
+		}
+		buf = buf[n:]
+	}
+	q := Quantity{value: math.MinInt64, base: PrefixQuecto, dim: newdim([]int{-127, -127, -127, -127, -127, -127, -127})}
+	if n := len(q.AppendVarint(nil)); n != MaxVarintQuantitySize {
+		t.Errorf("want maximum varint size %d, got %d", MaxVarintQuantitySize, n)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		buf, _ = q.AppendBinary(buf[:0])
+		buf = q.AppendVarint(buf[:0])
+	})
+	if allocs != 0 {
+		t.Error("append allocated", allocs)
+	}
+}
+
+func TestQuantityBinaryErrors(t *testing.T) {
+	var tests = []struct {
+		Data []byte
+		Err  error
+	}{
+		0:  {Data: nil, Err: io.ErrUnexpectedEOF},
+		1:  {Data: []byte{0}, Err: errBinaryVersion},
+		2:  {Data: []byte{3, 0, 0, 0}, Err: errBinaryVersion},
+		3:  {Data: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, Err: io.ErrUnexpectedEOF},
+		4:  {Data: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0}, Err: ErrInvalidPrefix},
+		5:  {Data: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x80, 0, 0, 0, 0, 0, 0}, Err: ErrDimOOB},
+		6:  {Data: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, Err: errBinaryTrailing},
+		7:  {Data: []byte{2, 0x80}, Err: io.ErrUnexpectedEOF},
+		8:  {Data: []byte{2, 0, 0}, Err: io.ErrUnexpectedEOF},
+		9:  {Data: []byte{2, 0, 0, 1}, Err: io.ErrUnexpectedEOF},
+		10: {Data: []byte{2, 0, 0, 0x80}, Err: ErrDimOOB},
+		11: {Data: []byte{2, 0, 0x1f, 0}, Err: ErrInvalidPrefix},
+		12: {Data: []byte{2, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0, 0}, Err: ErrOverflow},
+		13: {Data: []byte{2, 0, 0, 0, 0}, Err: errBinaryTrailing},
+	}
+	for i, test := range tests {
+		q := Quantity{value: 1}
+		err := q.UnmarshalBinary(test.Data)
+		if !errors.Is(err, test.Err) {
+			t.Errorf("case %d: want %v, got %v", i, test.Err, err)
+		}
+		if q.value != 1 {
+			t.Errorf("case %d: quantity modified on error", i)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package si
+
+// DerivedUnit is a named unit defined as a product of powers of the SI base units.
+type DerivedUnit struct {
+	// Name is the full name of the unit, i.e: "watt".
+	Name string
+	// Symbol is the unit symbol, i.e: "W".
+	Symbol string
+	// Dim is the dimension of the unit.
+	Dim Dimension
+}
+
+// SIDerivedUnits returns the 22 coherent derived units of the SI with special names and symbols
+// in the order they are listed in the SI brochure. A new slice is returned on every call.
+//
+// Some derived units share a dimension with other units: radian and steradian are dimensionless,
+// degree Celsius has the dimension of kelvin, lumen the dimension of candela, becquerel the dimension of hertz
+// and gray the dimension of sievert. When formatting the first unit listed is used and units
+// which are dimensionless or equivalent to a base unit are never used. Degree Celsius is not accepted
+// by [ParseQuantity] since a Celsius temperature is offset from kelvin: "20°C" is not 20K. Neither are
+// becquerel, gray, sievert and katal, which would be used to format any s⁻¹, m²·s⁻² or mol·s⁻¹ quantity.
+func SIDerivedUnits() []DerivedUnit {
+	return []DerivedUnit{
+		{Name: "radian", Symbol: "rad", Dim: mustDim(0, 0, 0, 0, 0, 0, 0)},
+		{Name: "steradian", Symbol: "sr", Dim: mustDim(0, 0, 0, 0, 0, 0, 0)},
+		{Name: "hertz", Symbol: "Hz", Dim: mustDim(0, 0, -1, 0, 0, 0, 0)},
+		{Name: "newton", Symbol: "N", Dim: mustDim(1, 1, -2, 0, 0, 0, 0)},
+		{Name: "pascal", Symbol: "Pa", Dim: mustDim(-1, 1, -2, 0, 0, 0, 0)},
+		{Name: "joule", Symbol: "J", Dim: mustDim(2, 1, -2, 0, 0, 0, 0)},
+		{Name: "watt", Symbol: "W", Dim: mustDim(2, 1, -3, 0, 0, 0, 0)},
+		{Name: "coulomb", Symbol: "C", Dim: mustDim(0, 0, 1, 0, 1, 0, 0)},
+		{Name: "volt", Symbol: "V", Dim: mustDim(2, 1, -3, 0, -1, 0, 0)},
+		{Name: "farad", Symbol: "F", Dim: mustDim(-2, -1, 4, 0, 2, 0, 0)},
+		{Name: "ohm", Symbol: "Ω", Dim: mustDim(2, 1, -3, 0, -2, 0, 0)},
+		{Name: "siemens", Symbol: "S", Dim: mustDim(-2, -1, 3, 0, 2, 0, 0)},
+		{Name: "weber", Symbol: "Wb", Dim: mustDim(2, 1, -2, 0, -1, 0, 0)},
+		{Name: "tesla", Symbol: "T", Dim: mustDim(0, 1, -2, 0, -1, 0, 0)},
+		{Name: "henry", Symbol: "H", Dim: mustDim(2, 1, -2, 0, -2, 0, 0)},
+		{Name: "degree Celsius", Symbol: "°C", Dim: mustDim(0, 0, 0, 1, 0, 0, 0)},
+		{Name: "lumen", Symbol: "lm", Dim: mustDim(0, 0, 0, 0, 0, 1, 0)},
+		{Name: "lux", Symbol: "lx", Dim: mustDim(-2, 0, 0, 0, 0, 1, 0)},
+		{Name: "becquerel", Symbol: "Bq", Dim: mustDim(0, 0, -1, 0, 0, 0, 0)},
+		{Name: "gray", Symbol: "Gy", Dim: mustDim(2, 0, -2, 0, 0, 0, 0)},
+		{Name: "sievert", Symbol: "Sv", Dim: mustDim(2, 0, -2, 0, 0, 0, 0)},
+		{Name: "katal", Symbol: "kat", Dim: mustDim(0, 0, -1, 0, 0, 0, 1)},
+	}
+}
+
+// isFormattable reports whether u can be used to format its dimension. Units which are
+// dimensionless or are equivalent to a base unit are only used for parsing.
+func (u DerivedUnit) isFormattable() bool {
+	var nonzero int
+	for _, exp := range u.Dim.dims {
+		if exp != 0 {
+			nonzero++
+			if exp != 1 {
+				return true
+			}
+		}
+	}
+	return nonzero > 1
+}
+
+// maxDerivedTerms is the maximum amount of derived units a simplified dimension is formatted with.
+const maxDerivedTerms = 3
+
+// dimPlan is the decomposition of a dimension into derived unit terms and base unit exponents.
+type dimPlan struct {
+	// derived holds index of derived unit and its exponent.
+	derived  [maxDerivedTerms][2]int
+	nderived int
+	base     [7]int
+}
+
+// term returns the symbol and exponent of the i'th term of the plan. Derived unit terms come first.
+func (p *dimPlan) term(df *DimensionFormatter, i int) (symbol string, exp int) {
+	if i < p.nderived {
+		return df.derived[p.derived[i][0]].Symbol, p.derived[i][1]
+	}
+	i -= p.nderived
+	return df.fmts[i], p.base[i]
+}
+
+// cost returns the amount of terms, the sum of absolute exponents and the sum of absolute
+// negative exponents of the plan. Lower costs are more compact representations.
+func (p *dimPlan) cost() (terms, exps, negs int) {
+	terms = p.nderived
+	for i := 0; i < p.nderived; i++ {
+		exps += iabs(p.derived[i][1])
+		if p.derived[i][1] < 0 {
+			negs -= p.derived[i][1]
+		}
+	}
+	for _, exp := range p.base {
+		if exp != 0 {
+			terms++
+			exps += iabs(exp)
+		}
+		if exp < 0 {
+			negs -= exp
+		}
+	}
+	return terms, exps, negs
+}
+
+// decompose finds how df formats dim. Dimensions matching a derived unit exactly are formatted
+// with the first such unit. If df simplifies, derived units are greedily factored out of dim
+// while doing so reduces the amount of terms. Among factors giving the same amount of terms the
+// one with the lowest sum of absolute exponents is chosen, then the one with the lowest sum of negative
+// exponents so that S·m⁻¹ is preferred over Ω⁻¹·m⁻¹, then the derived unit with the largest
+// sum of absolute exponents in its dimension. Remaining ties are broken by the order of derived units
+// and then by the exponent in the order 1, -1, 2, -2, 3, -3.
+func (df *DimensionFormatter) decompose(dim Dimension) (plan dimPlan) {
+	for i := range plan.base {
+		plan.base[i] = int(dim.dims[i])
+	}
+	for i, u := range df.derived {
+		if u.Dim == dim && u.isFormattable() {
+			plan.derived[0] = [2]int{i, 1}
+			plan.nderived = 1
+			plan.base = [7]int{}
+			return plan
+		}
+	}
+	if !df.simplify {
+		return plan
+	}
+	for plan.nderived < maxDerivedTerms {
+		planTerms, _, _ := plan.cost()
+		var best dimPlan
+		var bestTerms, bestExps, bestNegs, bestSize int
+		for i, u := range df.derived {
+			if !u.isFormattable() || plan.uses(i) {
+				continue
+			}
+			size := u.Dim.size()
+			for _, exp := range [...]int{1, -1, 2, -2, 3, -3} {
+				candidate := plan
+				candidate.derived[candidate.nderived] = [2]int{i, exp}
+				candidate.nderived++
+				oob := false
+				for j := range candidate.base {
+					candidate.base[j] -= exp * int(u.Dim.dims[j])
+					oob = oob || isDimOOB(candidate.base[j])
+				}
+				terms, exps, negs := candidate.cost()
+				if oob || terms >= planTerms {
+					continue
+				}
+				if best.nderived == 0 || terms < bestTerms || (terms == bestTerms && (exps < bestExps ||
+					(exps == bestExps && (negs < bestNegs || (negs == bestNegs && size > bestSize))))) {
+					best, bestTerms, bestExps, bestNegs, bestSize = candidate, terms, exps, negs, size
+				}
+			}
+		}
+		if best.nderived == 0 {
+			break // No improvement found.
+		}
+		plan = best
+	}
+	return plan
+}
+
+// uses reports whether the plan includes the derived unit at index i.
+func (p *dimPlan) uses(i int) bool {
+	for j := 0; j < p.nderived; j++ {
+		if p.derived[j][0] == i {
+			return true
+		}
+	}
+	return false
+}
+
+// size returns the sum of absolute exponents of d.
+func (d Dimension) size() (sum int) {
+	for _, exp := range d.dims {
+		sum += iabs(int(exp))
+	}
+	return sum
+}
+
+func iabs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func mustDim(L, M, T, K, I, J, N int) Dimension {
+	d, err := NewDimension(L, M, T, K, I, J, N)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
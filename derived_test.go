@@ -0,0 +1,185 @@
+package si
+
+import "testing"
+
+func TestSIDerivedUnits(t *testing.T) {
+	units := SIDerivedUnits()
+	if len(units) != 22 {
+		t.Fatalf("want 22 derived units, got %d", len(units))
+	}
+	seen := make(map[string]bool)
+	for _, u := range units {
+		if seen[u.Symbol] {
+			t.Errorf("duplicate symbol %q", u.Symbol)
+		}
+		seen[u.Symbol] = true
+	}
+	// Returned slice must not alias package state.
+	units[0].Symbol = "x"
+	if SIDerivedUnits()[0].Symbol != "rad" {
+		t.Error("SIDerivedUnits returned shared slice")
+	}
+}
+
+func TestDerivedFormat(t *testing.T) {
+	cfg := DefaultDimensionFormatterConfig()
+	cfg.DerivedUnits = SIDerivedUnits()
+	df, err := NewDimensionFormatter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		Dim  [7]int
+		Want string
+	}{
+		0:  {Dim: [7]int{2, 1, -3, 0, 0, 0, 0}, Want: "W"},
+		1:  {Dim: [7]int{2, 1, -3, 0, -1, 0, 0}, Want: "V"},
+		2:  {Dim: [7]int{2, 1, -3, 0, -2, 0, 0}, Want: "Ω"},
+		3:  {Dim: [7]int{0, 0, -1, 0, 0, 0, 0}, Want: "Hz"},
+		4:  {Dim: [7]int{0, 0, 1, 0, 1, 0, 0}, Want: "C"},
+		5:  {Dim: [7]int{2, 0, -2, 0, 0, 0, 0}, Want: "Gy"},
+		6:  {Dim: [7]int{0, 0, -1, 0, 0, 0, 1}, Want: "kat"},
+		7:  {Dim: [7]int{0, 0, 0, 1, 0, 0, 0}, Want: "K"},
+		8:  {Dim: [7]int{0, 0, 0, 0, 0, 1, 0}, Want: "cd"},
+		9:  {Dim: [7]int{1, 0, -1, 0, 0, 0, 0}, Want: "m·s⁻¹"},
+		10: {Dim: [7]int{4, 2, -6, 0, 0, 0, 0}, Want: "m⁴·kg²·s⁻⁶"},
+		11: {Dim: [7]int{}, Want: ""},
+	}
+	for i, test := range tests {
+		d := newdim(test.Dim[:])
+		got := df.StringDim(d)
+		if got != test.Want {
+			t.Errorf("case %d: want %q, got %q", i, test.Want, got)
+		}
+		if len(got) != df.sizeofFormat(d) {
+			t.Errorf("case %d: size mismatch, want %d, got %d", i, len(got), df.sizeofFormat(d))
+		}
+		back, n, err := df.ParseDimension(got)
+		if err != nil || n != len(got) || back != d {
+			t.Errorf("case %d: parse %q failed: %v", i, got, err)
+		}
+	}
+	// Formatter without derived units is unaffected.
+	si, _ := NewDimensionFormatter(DefaultDimensionFormatterConfig())
+	if got := si.StringDim(newdim(tests[0].Dim[:])); got != "m²·kg·s⁻³" {
+		t.Error("unexpected derived formatting", got)
+	}
+	cfg.DerivedUnits = []DerivedUnit{{Name: "empty"}}
+	_, err = NewDimensionFormatter(cfg)
+	if err == nil {
+		t.Error("expected error for empty derived unit symbol")
+	}
+}
+
+func TestParseDerivedQuantity(t *testing.T) {
+	var tests = []struct {
+		S     string
+		BaseU Prefix
+		Want  int64
+		Dim   [7]int
+	}{
+		0: {S: "4.7kΩ", BaseU: PrefixNone, Want: 4700, Dim: [7]int{2, 1, -3, 0, -2, 0, 0}},
+		1: {S: "3.5kN·m", BaseU: PrefixNone, Want: 3500, Dim: [7]int{2, 1, -2, 0, 0, 0, 0}},
+		2: {S: "60 Hz", BaseU: PrefixNone, Want: 60, Dim: [7]int{0, 0, -1, 0, 0, 0, 0}},
+		3: {S: "1.5T", BaseU: PrefixMilli, Want: 1500, Dim: [7]int{0, 1, -2, 0, -1, 0, 0}},
+		4: {S: "293K", BaseU: PrefixNone, Want: 293, Dim: [7]int{0, 0, 0, 1, 0, 0, 0}},
+		5: {S: "2rad", BaseU: PrefixNone, Want: 2},
+		6: {S: "1Pa", BaseU: PrefixNone, Want: 1, Dim: [7]int{-1, 1, -2, 0, 0, 0, 0}},
+		7: {S: "1PW", BaseU: PrefixTera, Want: 1000, Dim: [7]int{2, 1, -3, 0, 0, 0, 0}},
+		8: {S: "5mS", BaseU: PrefixMicro, Want: 5000, Dim: [7]int{-2, -1, 3, 0, 2, 0, 0}},
+		9: {S: "2 W·s", BaseU: PrefixNone, Want: 2, Dim: [7]int{2, 1, -2, 0, 0, 0, 0}},
+	}
+	for i, test := range tests {
+		q, n, err := ParseQuantity(test.S, test.BaseU)
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		} else if n != len(test.S) {
+			t.Errorf("case %d: bytes read mismatch, got %d want %d", i, n, len(test.S))
+		}
+		if q.Fixed() != test.Want || q.Dimension().Exponents() != test.Dim {
+			t.Errorf("case %d: want %d %v, got %d %v", i, test.Want, test.Dim, q.Fixed(), q.Dimension().Exponents())
+		}
+	}
+	q, _ := NewQuantity(4700, PrefixNone, newdim(tests[0].Dim[:]))
+	if q.String() != "4.7kΩ" {
+		t.Error("unexpected quantity string", q.String())
+	}
+	// Degree Celsius is offset from kelvin and is not parsed.
+	_, n, _ := ParseQuantity("20°C", PrefixNone)
+	if err := q.UnmarshalJSON([]byte(`"20°C"`)); err == nil || n != 2 {
+		t.Errorf("expected error parsing degree Celsius, got %s reading %d bytes", q, n)
+	}
+	// Radiation and catalytic activity units are opt-in.
+	for _, s := range []string{"5Bq", "5Gy", "5Sv", "5kat"} {
+		if q, n, _ := ParseQuantity(s, PrefixNone); n == len(s) {
+			t.Errorf("expected %q not to be parsed by default, got %s reading %d bytes", s, q, n)
+		}
+	}
+	cfg := DefaultDimensionFormatterConfig()
+	cfg.DerivedUnits = SIDerivedUnits()
+	df, _ := NewDimensionFormatter(cfg)
+	if d, n, err := df.ParseDimension("kat"); err != nil || n != 3 || d != newdim([]int{0, 0, -1, 0, 0, 0, 1}) {
+		t.Errorf("opt-in katal not parsed: %v %d %v", d, n, err)
+	}
+}
+
+func TestSimplifyFormat(t *testing.T) {
+	units := SIDerivedUnits()
+	byName := func(names ...string) (sub []DerivedUnit) {
+		for _, name := range names {
+			for _, u := range units {
+				if u.Name == name {
+					sub = append(sub, u)
+				}
+			}
+		}
+		return sub
+	}
+	var tests = []struct {
+		Units []DerivedUnit
+		Dim   [7]int
+		Want  string
+	}{
+		0: {Units: units, Dim: [7]int{2, 1, -2, -1, 0, 0, 0}, Want: "J·K⁻¹"},
+		1: {Units: units, Dim: [7]int{2, 1, -2, 0, 0, 0, 0}, Want: "J"},
+		2: {Units: units, Dim: [7]int{2, 1, -3, 0, 0, 0, 0}, Want: "W"},
+		3: {Units: units, Dim: [7]int{2, 1, -2, 0, 0, 0, -1}, Want: "J·mol⁻¹"},
+		4: {Units: units, Dim: [7]int{1, 0, -1, 0, 0, 0, 0}, Want: "m·s⁻¹"},
+		5: {Units: units, Dim: [7]int{4, 2, -6, 0, 0, 0, 0}, Want: "W²"},
+		6: {Units: units, Dim: [7]int{0, 0, 0, 1, 0, 0, 0}, Want: "K"},
+		7: {Units: units, Dim: [7]int{1, 1, -3, -1, 0, 0, 0}, Want: "W·m⁻¹·K⁻¹"},
+		// Simplification must reduce amount of terms.
+		8: {Units: units, Dim: [7]int{1, 0, -2, 0, 0, 0, 0}, Want: "m·s⁻²"},
+		9: {Units: units, Dim: [7]int{0, 1, -2, 0, 0, 0, -1}, Want: "kg·s⁻²·mol⁻¹"},
+		// Restricted unit sets.
+		10: {Units: byName("newton", "volt"), Dim: [7]int{2, 1, -2, 0, 0, 0, 0}, Want: "N·m"},
+		11: {Units: byName("volt"), Dim: [7]int{2, 1, -3, 0, 0, 0, 0}, Want: "V·A"},
+		12: {Units: byName("volt", "newton"), Dim: [7]int{2, 1, -3, 0, 0, 0, 0}, Want: "V·A"},
+		13: {Units: nil, Dim: [7]int{2, 1, -3, 0, 0, 0, 0}, Want: "m²·kg·s⁻³"},
+		// Positive exponents are preferred at equal cost.
+		14: {Units: units, Dim: [7]int{-3, -1, 3, 0, 2, 0, 0}, Want: "S·m⁻¹"},
+		15: {Units: units, Dim: [7]int{0, -1, 1, 0, 1, 0, 0}, Want: "C·kg⁻¹"},
+	}
+	for i, test := range tests {
+		cfg := DefaultDimensionFormatterConfig()
+		cfg.DerivedUnits = test.Units
+		cfg.Simplify = true
+		df, err := NewDimensionFormatter(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := newdim(test.Dim[:])
+		got := df.StringDim(d)
+		if got != test.Want {
+			t.Errorf("case %d: want %q, got %q", i, test.Want, got)
+		}
+		if len(got) != df.sizeofFormat(d) {
+			t.Errorf("case %d: size mismatch, want %d, got %d", i, len(got), df.sizeofFormat(d))
+		}
+		back, n, err := df.ParseDimension(got)
+		if err != nil || n != len(got) || back != d {
+			t.Errorf("case %d: parse %q failed: %v", i, got, err)
+		}
+	}
+}
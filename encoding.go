@@ -0,0 +1,205 @@
+package si
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+var errTrailingText = errors.New("unexpected text after value")
+
+// MarshalText implements [encoding.TextMarshaler]. The dimension is encoded
+// in the abstract form returned by [Dimension.String], i.e: "LM²T⁻³".
+// Dimensionless values are encoded as an empty string.
+func (d Dimension) MarshalText() ([]byte, error) {
+	return abstractDimFormatter.AppendFormat(nil, d), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. It accepts the abstract form
+// emitted by [Dimension.MarshalText], optionally with ASCII exponents: "L²MT⁻³" or "L^2MT^-3".
+// SI unit symbols are not accepted since some are also abstract letters with a different meaning,
+// i.e: "T" is time and "N" is amount of substance, not tesla or newton. An empty text is dimensionless.
+func (d *Dimension) UnmarshalText(text []byte) error {
+	s := string(text)
+	dim, n, err := abstractDimFormatter.ParseDimension(s)
+	if err != nil {
+		return err
+	} else if n != len(s) {
+		return errTrailingText
+	}
+	*d = dim
+	return nil
+}
+
+// MarshalText implements [encoding.TextMarshaler]. The prefix is encoded as its symbol
+// returned by [Prefix.String], i.e: "k" or "μ". [PrefixNone] is encoded as an empty string.
+// It returns [ErrInvalidPrefix] if p is not valid.
+func (p Prefix) MarshalText() ([]byte, error) {
+	if !p.IsValid() {
+		return nil, ErrInvalidPrefix
+	} else if p == PrefixNone {
+		return []byte{}, nil
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. It accepts the symbols parsed by
+// [ParsePrefix], including "u" for micro. An empty text is read as [PrefixNone].
+func (p *Prefix) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = PrefixNone
+		return nil
+	}
+	s := string(text)
+	pfx, n, err := ParsePrefix(s)
+	if err != nil {
+		return err
+	} else if n != len(s) {
+		return ErrUnknownPrefix.at(s, 0)
+	}
+	*p = pfx
+	return nil
+}
+
+// JSONForm selects how quantities are encoded to JSON.
+type JSONForm uint8
+
+const (
+	// JSONLossless encodes quantities as an object holding the fixed-point value, the exponent of
+	// the base prefix and the dimension text, i.e: {"value":4700,"prefix":0,"dim":"L²MT⁻³I⁻²"}.
+	JSONLossless JSONForm = iota
+	// JSONHuman encodes quantities as the string returned by [Quantity.String], i.e: "4.7kΩ".
+	// The value is exact but the base prefix is not preserved, see [Quantity.UnmarshalJSON].
+	JSONHuman
+)
+
+// QuantityJSONForm is the form used by [Quantity.MarshalJSON]. Use [LosslessQuantity]
+// or [HumanQuantity] to select the form of a single field. It should be set during initialization
+// since it is read without synchronization.
+var QuantityJSONForm = JSONLossless
+
+// quantityJSON is the lossless JSON representation of a quantity.
+type quantityJSON struct {
+	Value  int64     `json:"value"`
+	Prefix int       `json:"prefix"`
+	Dim    Dimension `json:"dim"`
+}
+
+// MarshalJSON implements [json.Marshaler] with the form selected by [QuantityJSONForm].
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	if QuantityJSONForm == JSONHuman {
+		return HumanQuantity{q}.MarshalJSON()
+	}
+	return LosslessQuantity{q}.MarshalJSON()
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It accepts both forms of [JSONForm] regardless
+// of [QuantityJSONForm] and JSON numbers, which are read as dimensionless quantities.
+// Strings and numbers are parsed as in [ParseQuantity] and stored in the coarsest engineering prefix
+// which represents them exactly, so "4.7kΩ" is stored as 4700 in base units and "3.3mA" as 3300 micro.
+// A JSON null leaves q unchanged.
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0:
+		return ErrNaN
+	case string(data) == "null":
+		return nil
+	case data[0] == '{':
+		var qj quantityJSON
+		err := json.Unmarshal(data, &qj)
+		if err != nil {
+			return err
+		}
+		if qj.Prefix < math.MinInt8 || qj.Prefix > math.MaxInt8 {
+			return ErrInvalidPrefix
+		}
+		nq, err := NewQuantity(qj.Value, Prefix(qj.Prefix), qj.Dim)
+		if err != nil {
+			return err
+		}
+		*q = nq
+		return nil
+	}
+	s := string(data)
+	if data[0] == '"' {
+		err := json.Unmarshal(data, &s)
+		if err != nil {
+			return err
+		}
+	}
+	nq, err := parseQuantityText(s)
+	if err != nil {
+		return err
+	}
+	*q = nq
+	return nil
+}
+
+// parseQuantityText parses all of s as in [parseQuantityNatural].
+func parseQuantityText(s string) (Quantity, error) {
+	q, n, err := parseQuantityNatural(s)
+	if err != nil {
+		return Quantity{}, err
+	} else if n != len(s) {
+		return Quantity{}, ErrUnknownPrefix.at(s, n)
+	}
+	return q, nil
+}
+
+// parseQuantityNatural parses s as in [ParseQuantity] and expresses the result in the coarsest
+// engineering prefix that represents it exactly. Values which do not fit in an int64 in that prefix
+// are stored in the next larger prefix they fit in, rounding half away from zero.
+func parseQuantityNatural(s string) (q Quantity, readBytes int, err error) {
+	d, readBytes, err := parseDecimal(s)
+	if err != nil {
+		return Quantity{}, 0, err
+	}
+	scale, dim, n, err := parseUnit(s[readBytes:])
+	if err != nil {
+		return Quantity{}, 0, parseErrorAt(err, s, readBytes)
+	}
+	if d.base == 0 {
+		return Quantity{dim: dim}, readBytes + n, nil
+	}
+	for d.base%10 == 0 {
+		d.base /= 10
+		d.exp++
+	}
+	exp := d.exp + scale
+	hi, lo, ok := mul128Pow10(0, d.base, mod3(exp))
+	if ok {
+		var v int64
+		var base Prefix
+		v, base, ok = fit128(hi, lo, d.neg, exp-mod3(exp))
+		if ok {
+			return Quantity{value: v, base: base, dim: dim}, readBytes + n, nil
+		}
+	}
+	return Quantity{}, 0, d.overflowErr().at(s, 0)
+}
+
+// LosslessQuantity is a [Quantity] which is always encoded to JSON in the [JSONLossless] form.
+type LosslessQuantity struct {
+	Quantity
+}
+
+// MarshalJSON implements [json.Marshaler] with the [JSONLossless] form.
+func (q LosslessQuantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(quantityJSON{Value: q.value, Prefix: q.base.Exponent(), Dim: q.dim})
+}
+
+// HumanQuantity is a [Quantity] which is always encoded to JSON in the [JSONHuman] form.
+type HumanQuantity struct {
+	Quantity
+}
+
+// MarshalJSON implements [json.Marshaler] with the [JSONHuman] form. Formatting errors are returned instead of written to the string.
+func (q HumanQuantity) MarshalJSON() ([]byte, error) {
+	b, err := q.appendMagnitude(make([]byte, 0, 24), siDimFormatter, 'f', -1)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(siDimFormatter.AppendFormat(b, q.dim)))
+}
@@ -0,0 +1,214 @@
+package si
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDimensionText(t *testing.T) {
+	var tests = []struct {
+		Dim  [7]int
+		Text string
+	}{
+		0: {Dim: [7]int{2, 1, -3, 0, 0, 0, 0}, Text: "L²MT⁻³"},
+		1: {Dim: [7]int{0, 0, 0, 1, 0, 0, 0}, Text: "K"},
+		2: {Dim: [7]int{-127, 127, 0, 0, 0, 0, 1}, Text: "L⁻¹²⁷M¹²⁷N"},
+		3: {Dim: [7]int{}, Text: ""},
+	}
+	for i, test := range tests {
+		d := newdim(test.Dim[:])
+		text, err := d.MarshalText()
+		if err != nil || string(text) != test.Text {
+			t.Errorf("case %d: want %q, got %q (%v)", i, test.Text, text, err)
+		}
+		var got Dimension
+		err = got.UnmarshalText(text)
+		if err != nil || got != d {
+			t.Errorf("case %d: unmarshal %q got %v (%v)", i, text, got, err)
+		}
+	}
+	// Symbols shared with SI units are read in the abstract form.
+	for s, want := range map[string][7]int{
+		"T":        {0, 0, 1, 0, 0, 0, 0},
+		"N":        {0, 0, 0, 0, 0, 0, 1},
+		"J":        {0, 0, 0, 0, 0, 1, 0},
+		"L^2MT^-3": {2, 1, -3, 0, 0, 0, 0},
+	} {
+		var got Dimension
+		if err := got.UnmarshalText([]byte(s)); err != nil || got.Exponents() != want {
+			t.Errorf("unmarshal %q got %v (%v)", s, got, err)
+		}
+	}
+	// SI form is not accepted.
+	for i, s := range []string{"LM?", "x", "m·", "L^x", "m²·kg·s⁻³", "W", "m^2*kg/s^3"} {
+		var got Dimension
+		if err := got.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("error case %d: expected error for %q, got %v", i, s, got)
+		}
+	}
+}
+
+func TestPrefixText(t *testing.T) {
+	for p := PrefixQuecto; p <= PrefixQuetta; p++ {
+		if !p.IsValid() {
+			if _, err := p.MarshalText(); err == nil {
+				t.Errorf("%d: expected error for invalid prefix", p)
+			}
+			continue
+		}
+		text, err := p.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Prefix
+		err = got.UnmarshalText(text)
+		if err != nil || got != p {
+			t.Errorf("%d: unmarshal %q got %d (%v)", p, text, got, err)
+		}
+	}
+	var got Prefix = PrefixKilo
+	if err := got.UnmarshalText(nil); err != nil || got != PrefixNone {
+		t.Errorf("empty text got %d (%v)", got, err)
+	}
+	if err := got.UnmarshalText([]byte("u")); err != nil || got != PrefixMicro {
+		t.Errorf("u got %d (%v)", got, err)
+	}
+	for i, s := range []string{"x", "kk", "K", " "} {
+		if err := got.UnmarshalText([]byte(s)); err == nil {
+			t.Errorf("error case %d: expected error for %q", i, s)
+		}
+	}
+}
+
+func TestTextJSON(t *testing.T) {
+	type config struct {
+		Dim    Dimension
+		Prefix Prefix
+	}
+	want := config{Dim: newdim([]int{1, 0, -2, 0, 0, 0, 0}), Prefix: PrefixMilli}
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"Dim":"LT⁻²","Prefix":"m"}` {
+		t.Error("unexpected JSON", string(b))
+	}
+	var got config
+	err = json.Unmarshal(b, &got)
+	if err != nil || got != want {
+		t.Errorf("round trip failed: %v %v", got, err)
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	ohm := newdim([]int{2, 1, -3, 0, -2, 0, 0})
+	q, _ := NewQuantity(4700, PrefixNone, ohm)
+	b, err := json.Marshal(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"value":4700,"prefix":0,"dim":"L²MT⁻³I⁻²"}` {
+		t.Error("unexpected lossless JSON", string(b))
+	}
+	var got Quantity
+	err = json.Unmarshal(b, &got)
+	if err != nil || got != q {
+		t.Errorf("lossless round trip failed: %v %v", got, err)
+	}
+	b, _ = json.Marshal(HumanQuantity{q})
+	if string(b) != `"4.7kΩ"` {
+		t.Error("unexpected human JSON", string(b))
+	}
+	err = json.Unmarshal(b, &got)
+	if err != nil || got != q {
+		t.Errorf("human round trip failed: %v %v", got, err)
+	}
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	for i, test := range []struct {
+		Q    Quantity
+		JSON string
+		Back Quantity // Quantity unmarshaled in its natural base.
+	}{
+		0: {Q: Quantity{value: 5, base: PrefixMilli}, JSON: `"5e-03"`, Back: Quantity{value: 5, base: PrefixMilli}},
+		1: {Q: Quantity{value: 1, base: PrefixTera}, JSON: `"1e+12"`, Back: Quantity{value: 1, base: PrefixTera}},
+		2: {Q: Quantity{value: 5_000, base: PrefixMicro}, JSON: `"5e-03"`, Back: Quantity{value: 5, base: PrefixMilli}},
+		3: {Q: Quantity{value: 9e18, base: PrefixExa, dim: current}, JSON: `"9e+36A"`, Back: Quantity{value: 9e6, base: PrefixQuetta, dim: current}},
+	} {
+		b, err = json.Marshal(HumanQuantity{test.Q})
+		if err != nil || string(b) != test.JSON {
+			t.Errorf("case %d: want %s, got %s (%v)", i, test.JSON, b, err)
+			continue
+		}
+		err = json.Unmarshal(b, &got)
+		if err != nil || got != test.Back {
+			t.Errorf("case %d: human round trip got %d%s %s (%v)", i, got.Fixed(), got.Base(), got.Dimension(), err)
+		}
+	}
+
+	// Global and per field form selection.
+	type fields struct {
+		Q Quantity
+		L LosslessQuantity
+		H HumanQuantity
+	}
+	QuantityJSONForm = JSONHuman
+	b, err = json.Marshal(fields{Q: q, L: LosslessQuantity{q}, H: HumanQuantity{q}})
+	QuantityJSONForm = JSONLossless
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"Q":"4.7kΩ","L":{"value":4700,"prefix":0,"dim":"L²MT⁻³I⁻²"},"H":"4.7kΩ"}` {
+		t.Error("unexpected JSON", string(b))
+	}
+	var f fields
+	err = json.Unmarshal(b, &f)
+	if err != nil || f.Q != q || f.L.Quantity != q || f.H.Quantity != q {
+		t.Errorf("fields round trip failed: %+v %v", f, err)
+	}
+}
+
+func TestQuantityUnmarshalJSON(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	var tests = []struct {
+		JSON  string
+		V     int64
+		BaseU Prefix
+		Dim   Dimension
+	}{
+		0: {JSON: `"3.3mA"`, V: 3300, BaseU: PrefixMicro, Dim: current},
+		1: {JSON: `"4.70k"`, V: 4700, BaseU: PrefixNone},
+		2: {JSON: `"3A"`, V: 3, BaseU: PrefixNone, Dim: current},
+		3: {JSON: `"2.5 mA"`, V: 2500, BaseU: PrefixMicro, Dim: current},
+		4: {JSON: `"0 A"`, V: 0, BaseU: PrefixNone, Dim: current},
+		5: {JSON: `4.7`, V: 4700, BaseU: PrefixMilli},
+		6: {JSON: `-1e3`, V: -1, BaseU: PrefixKilo},
+		7: {JSON: `{"value":-5,"prefix":-3,"dim":"I"}`, V: -5, BaseU: PrefixMilli, Dim: current},
+		8: {JSON: `{"value":5,"prefix":-3,"dim":"I^1"}`, V: 5, BaseU: PrefixMilli, Dim: current},
+		9: {JSON: `{"value":5}`, V: 5, BaseU: PrefixNone},
+		// Values which do not fit in the natural base use a larger base.
+		10: {JSON: `"9.3e18k"`, V: 9300, BaseU: PrefixExa},
+		11: {JSON: `"9223372036854775807"`, V: 9_223_372_036_854_775_807, BaseU: PrefixNone},
+		12: {JSON: `"92233720368547758.07k"`, V: 92_233_720_368_547_758, BaseU: PrefixKilo},
+	}
+	for i, test := range tests {
+		var got Quantity
+		err := json.Unmarshal([]byte(test.JSON), &got)
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		if got.Fixed() != test.V || got.Base() != test.BaseU || got.Dimension() != test.Dim {
+			t.Errorf("case %d: want %d %d %v, got %d %d %v", i, test.V, test.BaseU, test.Dim, got.Fixed(), got.Base(), got.Dimension())
+		}
+	}
+	for i, s := range []string{`"4.7x"`, `"x"`, `{"value":5,"prefix":1000}`, `{"value":5,"prefix":4}`, `{"value":"5"}`, `"5 m^x"`, `true`, `"1e40Q"`, `{"value":5,"dim":"A"}`} {
+		var got Quantity
+		if err := json.Unmarshal([]byte(s), &got); err == nil {
+			t.Errorf("error case %d: expected error for %s, got %v", i, s, got)
+		}
+	}
+	q, _ := NewQuantity(1, PrefixKilo, current)
+	if err := json.Unmarshal([]byte("null"), &q); err != nil || q.Fixed() != 1 {
+		t.Error("null modified quantity", q, err)
+	}
+}
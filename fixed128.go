@@ -0,0 +1,187 @@
+package si
+
+import (
+	"math/bits"
+	"strconv"
+)
+
+// Fixed128 is a 128-bit signed fixed-point value for quantities whose dynamic range exceeds an int64,
+// i.e: attoamperes over a kiloampere range. Like the int64 values of [ParseFixed] and [AppendFixed] it is
+// expressed in units of a base prefix which is not stored in the value. It holds up to 38 significant digits.
+// The zero value of a Fixed128 is zero.
+type Fixed128 struct {
+	// hi and lo are the high and low words of the two's complement representation.
+	hi, lo uint64
+}
+
+// Fixed128FromInt64 returns v as a [Fixed128].
+func Fixed128FromInt64(v int64) Fixed128 {
+	return Fixed128{hi: uint64(v >> 63), lo: uint64(v)}
+}
+
+// Int64 returns x as an int64 and true if it fits in an int64.
+func (x Fixed128) Int64() (int64, bool) {
+	v := int64(x.lo)
+	return v, uint64(v>>63) == x.hi
+}
+
+// Sign returns -1 if x is negative, 0 if x is zero and +1 if x is positive.
+func (x Fixed128) Sign() int {
+	switch {
+	case int64(x.hi) < 0:
+		return -1
+	case x.hi == 0 && x.lo == 0:
+		return 0
+	}
+	return 1
+}
+
+// String returns the base 10 representation of x's fixed-point value, i.e: "-12345678901234567890123".
+func (x Fixed128) String() string {
+	hi, lo, neg := x.abs()
+	var buf [40]byte
+	b := buf[:0]
+	if neg {
+		b = append(b, '-')
+	}
+	return string(appendUint128(b, hi, lo))
+}
+
+// abs returns the 128-bit magnitude of x and whether x is negative.
+func (x Fixed128) abs() (hi, lo uint64, neg bool) {
+	if int64(x.hi) >= 0 {
+		return x.hi, x.lo, false
+	}
+	hi, lo = neg128(x.hi, x.lo)
+	return hi, lo, true
+}
+
+// fixed128 returns the signed representation of the 128-bit magnitude hi:lo. Returns false if it overflows.
+func fixed128(hi, lo uint64, neg bool) (Fixed128, bool) {
+	if hi > 1<<63 || hi == 1<<63 && (lo != 0 || !neg) {
+		return Fixed128{}, false
+	} else if neg {
+		hi, lo = neg128(hi, lo)
+	}
+	return Fixed128{hi: hi, lo: lo}, true
+}
+
+// neg128 returns the two's complement negation of hi:lo.
+func neg128(hi, lo uint64) (uint64, uint64) {
+	lo, borrow := bits.Sub64(0, lo, 0)
+	hi, _ = bits.Sub64(0, hi, borrow)
+	return hi, lo
+}
+
+// appendUint128 appends the base 10 digits of the 128-bit magnitude hi:lo to b.
+func appendUint128(b []byte, hi, lo uint64) []byte {
+	if hi == 0 {
+		return strconv.AppendUint(b, lo, 10)
+	}
+	const pow19 = 1e19 // Largest power of ten that fits in a uint64.
+	qhi, r := bits.Div64(0, hi, pow19)
+	qlo, r := bits.Div64(r, lo, pow19)
+	b = appendUint128(b, qhi, qlo)
+	var buf [19]byte
+	digits := strconv.AppendUint(buf[:0], r, 10)
+	for i := len(digits); i < len(buf); i++ {
+		b = append(b, '0')
+	}
+	return append(b, digits...)
+}
+
+// ParseFixed128 is like [ParseFixed] but parses values with up to 39 significant digits into a [Fixed128],
+// i.e: "12345678901234567" parsed in [PrefixMilli] base units. [ErrOverflowsInt64] and [ErrOverflowsInt64Negative]
+// are returned if the value exceeds the range of a Fixed128.
+func ParseFixed128(s string, baseUnits Prefix) (value Fixed128, readBytes int, err error) {
+	return ParseFixed128Round(s, baseUnits, RoundHalfUp)
+}
+
+// ParseFixed128Round is like [ParseFixed128] but rounds digits finer than baseUnits with mode as in [ParseFixedRound].
+func ParseFixed128Round(s string, baseUnits Prefix, mode RoundingMode) (value Fixed128, readBytes int, err error) {
+	if !baseUnits.IsValid() {
+		return Fixed128{}, 0, ErrInvalidPrefix
+	} else if !mode.IsValid() {
+		return Fixed128{}, 0, ErrInvalidRoundingMode
+	}
+	d, hi, lo, readBytes, err := parseDecimal128(s)
+	if err != nil {
+		return Fixed128{}, 0, err
+	}
+	var incomingPrefix Prefix
+	if readBytes < len(s) {
+		var n int
+		incomingPrefix, n, err = ParsePrefix(s[readBytes:])
+		if err != nil {
+			return Fixed128{}, 0, parseErrorAt(err, s, readBytes)
+		}
+		readBytes += n
+	}
+	hi, lo, ok := scale128(hi, lo, d.neg, d.exp+int(incomingPrefix-baseUnits), mode)
+	if ok {
+		value, ok = fixed128(hi, lo, d.neg)
+	}
+	if !ok {
+		return Fixed128{}, 0, d.overflowErr().at(s, 0)
+	}
+	return value, readBytes, nil
+}
+
+// parseDecimal128 is like [parseDecimal] but parses up to 39 significant digits into the 128-bit magnitude hi:lo.
+// The base field of the returned decimal is not set.
+func parseDecimal128(s string) (d decimal, hi, lo uint64, readBytes int, err error) {
+	var buf [39]byte
+	d, digits, readBytes, err := scanDecimal(s, buf[:])
+	if err != nil {
+		return decimal{}, 0, 0, 0, err
+	}
+	for _, c := range digits {
+		var ok bool
+		var carry uint64
+		hi, lo, ok = mul128Pow10(hi, lo, 1)
+		lo, carry = bits.Add64(lo, uint64(c-'0'), 0)
+		hi, carry = bits.Add64(hi, 0, carry)
+		if !ok || carry != 0 {
+			return decimal{}, 0, 0, 0, d.overflowErr().at(s, 0)
+		}
+	}
+	return d, hi, lo, readBytes, nil
+}
+
+// Rescale128 is like [Rescale] for [Fixed128] values.
+func Rescale128(value Fixed128, from, to Prefix, mode RoundingMode) (Fixed128, error) {
+	if !from.IsValid() || !to.IsValid() {
+		return Fixed128{}, ErrInvalidPrefix
+	} else if !mode.IsValid() {
+		return Fixed128{}, ErrInvalidRoundingMode
+	}
+	hi, lo, neg := value.abs()
+	hi, lo, ok := scale128(hi, lo, neg, int(from)-int(to), mode)
+	if ok {
+		value, ok = fixed128(hi, lo, neg)
+	}
+	if !ok {
+		return Fixed128{}, ErrOverflow
+	}
+	return value, nil
+}
+
+// AppendFixed128 is like [AppendFixed] for [Fixed128] values. Precision may be up to 40 digits.
+func AppendFixed128(b []byte, value Fixed128, baseUnits Prefix, fmt byte, prec int) []byte {
+	return FixedFormat{Fmt: fmt, Prec: prec}.Append128(b, value, baseUnits)
+}
+
+// Append128 is like [FixedFormat.Append] for [Fixed128] values.
+func (f FixedFormat) Append128(b []byte, value Fixed128, baseUnits Prefix) []byte {
+	res, err := f.AppendErr128(b, value, baseUnits)
+	if err != nil {
+		return append(b, formatErrorString(err)...)
+	}
+	return res
+}
+
+// AppendErr128 is like [FixedFormat.AppendErr] for [Fixed128] values. Precision may be up to 40 digits.
+func (f FixedFormat) AppendErr128(b []byte, value Fixed128, baseUnits Prefix) ([]byte, error) {
+	hi, lo, neg := value.abs()
+	return f.appendErr(b, hi, lo, neg, baseUnits, 40)
+}
@@ -0,0 +1,206 @@
+package si
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestParseFixed128(t *testing.T) {
+	var tests = []struct {
+		S     string
+		BaseU Prefix
+		Want  string
+	}{
+		0: {S: "12345678901234567", BaseU: PrefixMilli, Want: "12345678901234567000"},
+		1: {S: "1.000000000000000000001k", BaseU: PrefixAtto, Want: "1000000000000000000001"},
+		2: {S: "-2.5mA", BaseU: PrefixAtto, Want: "-2500000000000000"},
+		3: {S: "170141183460469231731687303715884105727", BaseU: PrefixNone, Want: "170141183460469231731687303715884105727"},
+		4: {S: "-170141183460469231731687303715884105728", BaseU: PrefixNone, Want: "-170141183460469231731687303715884105728"},
+		5: {S: "1", BaseU: PrefixQuecto, Want: "1000000000000000000000000000000"},
+		6: {S: "0.000", BaseU: PrefixNone, Want: "0"},
+		7: {S: "1.5e-3", BaseU: PrefixMilli, Want: "2"},
+		8: {S: "9223372036854775808", BaseU: PrefixNone, Want: "9223372036854775808"},
+	}
+	for i, test := range tests {
+		got, n, err := ParseFixed128(test.S, test.BaseU)
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		wantN := len(test.S)
+		if test.S[len(test.S)-1] == 'A' {
+			wantN--
+		}
+		if got.String() != test.Want || n != wantN {
+			t.Errorf("case %d: want %s, got %s (read %d)", i, test.Want, got, n)
+		}
+	}
+	for i, test := range []struct {
+		S   string
+		Err error
+	}{
+		0: {S: "170141183460469231731687303715884105728", Err: ErrOverflowsInt64},
+		1: {S: "-170141183460469231731687303715884105729", Err: ErrOverflowsInt64Negative},
+		2: {S: "1234567890123456789012345678901234567890", Err: ErrOverflowsInt64},
+		3: {S: "999999999999999999999999999999999999999", Err: ErrOverflowsInt64},
+		4: {S: "1e39", Err: ErrOverflowsInt64},
+		5: {S: "1x", Err: ErrUnknownPrefix},
+		6: {S: "--1", Err: ErrMinusMinus},
+		7: {S: "", Err: ErrNaN},
+		8: {S: "1Q", Err: ErrOverflowsInt64}, // 1e60 quecto.
+	} {
+		_, _, err := ParseFixed128(test.S, PrefixQuecto)
+		if !errors.Is(err, test.Err) {
+			t.Errorf("error case %d: want %v, got %v", i, test.Err, err)
+		}
+	}
+	if _, _, err := ParseFixed128("1", 4); err != ErrInvalidPrefix {
+		t.Error("expected invalid prefix error, got", err)
+	}
+	if _, _, err := ParseFixed128Round("1", PrefixNone, roundingModeInvalid); err != ErrInvalidRoundingMode {
+		t.Error("expected invalid rounding mode error, got", err)
+	}
+	// Rounding modes as in ParseFixedRound.
+	for mode := RoundingMode(0); mode < roundingModeInvalid; mode++ {
+		want, _, _ := ParseFixedRound("-2.5", PrefixNone, mode)
+		got, _, err := ParseFixed128Round("-2.5", PrefixNone, mode)
+		if v, ok := got.Int64(); err != nil || !ok || v != want {
+			t.Errorf("%s: want %d, got %s (%v)", mode, want, got, err)
+		}
+	}
+}
+
+func TestAppendFixed128(t *testing.T) {
+	var tests = []struct {
+		S     string // Parsed in BaseU.
+		BaseU Prefix
+		Fmt   byte
+		Prec  int
+		Want  string
+	}{
+		0: {S: "1000000000000000000001", BaseU: PrefixAtto, Fmt: 'f', Prec: 22, Want: "1.000000000000000000001k"},
+		1: {S: "1000000000000000000001", BaseU: PrefixAtto, Fmt: 'f', Prec: 3, Want: "1k"},
+		2: {S: "12345678901234567000", BaseU: PrefixMilli, Fmt: 'f', Prec: 3, Want: "12.3P"},
+		3: {S: "170141183460469231731687303715884105727", BaseU: PrefixNone, Fmt: 'e', Prec: 40, Want: "1.70141183460469231731687303715884105727e+38"},
+		4: {S: "170141183460469231731687303715884105727", BaseU: PrefixNone, Fmt: 'e', Prec: 3, Want: "1.7e+38"},
+		5: {S: "999999999999999999999999", BaseU: PrefixNone, Fmt: 'f', Prec: 3, Want: "1Y"},
+		6: {S: "2500000000000000", BaseU: PrefixAtto, Fmt: 'g', Prec: 4, Want: "2.500m"},
+	}
+	var buf [64]byte
+	for i, test := range tests {
+		v, _, err := ParseFixed128(test.S, PrefixNone)
+		if err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+		got := AppendFixed128(buf[:0], v, test.BaseU, test.Fmt, test.Prec)
+		if string(got) != test.Want {
+			t.Errorf("case %d: want %s, got %s", i, test.Want, got)
+		}
+		neg, _, _ := ParseFixed128("-"+test.S, PrefixNone)
+		got = AppendFixed128(buf[:0], neg, test.BaseU, test.Fmt, test.Prec)
+		if string(got) != "-"+test.Want {
+			t.Errorf("case %d: want -%s, got %s", i, test.Want, got)
+		}
+	}
+	// Equivalent to AppendFixed for int64 values.
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		v := rng.Int63() >> rng.Intn(63)
+		base := Prefix(rng.Intn(21)*3 - 30)
+		prec := 1 + rng.Intn(20)
+		want := AppendFixed(nil, v, base, 'g', prec)
+		got := AppendFixed128(buf[:0], Fixed128FromInt64(v), base, 'g', prec)
+		if string(got) != string(want) {
+			t.Fatalf("%d %d %d: want %s, got %s", v, base, prec, want, got)
+		}
+	}
+	if got := AppendFixed128(buf[:0], Fixed128{}, PrefixNone, 'f', 41); string(got) != "<si!LARGE PREC>" {
+		t.Error("expected precision error, got", string(got))
+	}
+}
+
+func TestFormatParseFixed128Loop(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var buf [64]byte
+	for i := 0; i < 1000; i++ {
+		v := Fixed128{hi: rng.Uint64() >> rng.Intn(64), lo: rng.Uint64()}
+		if rng.Intn(2) == 0 {
+			v.hi, v.lo = neg128(v.hi, v.lo)
+		}
+		base := Prefix(rng.Intn(21)*3 - 30)
+		s := AppendFixed128(buf[:0], v, base, 'e', 40)
+		got, n, err := ParseFixed128(string(s), base)
+		if err != nil || n != len(s) || got != v {
+			t.Fatalf("%s %d: got %s (%v), formatted %q", v, base, got, err, s)
+		}
+	}
+}
+
+func TestRescale128(t *testing.T) {
+	// Equivalent to Rescale for int64 values.
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		v := rng.Int63() >> rng.Intn(63)
+		if rng.Intn(2) == 0 {
+			v = -v
+		}
+		from := Prefix(rng.Intn(21)*3 - 30)
+		to := Prefix(rng.Intn(21)*3 - 30)
+		mode := RoundingMode(rng.Intn(int(roundingModeInvalid)))
+		want, wantErr := Rescale(v, from, to, mode)
+		got, err := Rescale128(Fixed128FromInt64(v), from, to, mode)
+		if wantErr != nil {
+			continue // Fits in 128 bits.
+		}
+		if g, ok := got.Int64(); err != nil || !ok || g != want {
+			t.Fatalf("%d %d->%d %s: want %d, got %s (%v)", v, from, to, mode, want, got, err)
+		}
+	}
+	v, _, _ := ParseFixed128("12345678901234567", PrefixNone)
+	got, err := Rescale128(v, PrefixNone, PrefixAtto, RoundHalfUp)
+	if err != nil || got.String() != "12345678901234567000000000000000000" {
+		t.Error("unexpected rescale", got, err)
+	}
+	for i, test := range []struct {
+		V        Fixed128
+		From, To Prefix
+		Mode     RoundingMode
+		Err      error
+	}{
+		0: {V: v, From: PrefixNone, To: PrefixQuecto, Err: ErrOverflow},
+		1: {V: v, From: 4, To: PrefixNone, Err: ErrInvalidPrefix},
+		2: {V: v, From: PrefixNone, To: PrefixNone, Mode: roundingModeInvalid, Err: ErrInvalidRoundingMode},
+	} {
+		if _, err := Rescale128(test.V, test.From, test.To, test.Mode); err != test.Err {
+			t.Errorf("error case %d: want %v, got %v", i, test.Err, err)
+		}
+	}
+}
+
+func TestFixed128Int64(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, math.MaxInt64, math.MinInt64} {
+		x := Fixed128FromInt64(v)
+		if got, ok := x.Int64(); !ok || got != v {
+			t.Errorf("%d: got %d", v, got)
+		}
+		wantSign := 0
+		if v > 0 {
+			wantSign = 1
+		} else if v < 0 {
+			wantSign = -1
+		}
+		if x.Sign() != wantSign {
+			t.Errorf("%d: got sign %d", v, x.Sign())
+		}
+	}
+	v, _, _ := ParseFixed128("9223372036854775808", PrefixNone)
+	if _, ok := v.Int64(); ok {
+		t.Error("expected int64 overflow")
+	}
+	v, _, _ = ParseFixed128("-9223372036854775809", PrefixNone)
+	if _, ok := v.Int64(); ok {
+		t.Error("expected int64 overflow")
+	}
+}
@@ -0,0 +1,107 @@
+package si
+
+import (
+	"flag"
+	"fmt"
+)
+
+// FixedValue is a fixed-point command-line flag value implementing [flag.Value] and [flag.Getter].
+// It also implements the Type method required by the pflag package. The flag is read as a quantity
+// with the unit's dimension or as a number with an optional prefix as in [ParseFixed], so a flag of
+// electric current accepts "2.5mA", "2.5 mA" and "2.5m". A flag of length reads "5m" as five metres.
+type FixedValue struct {
+	p    *int64
+	base Prefix
+	dim  Dimension
+}
+
+// NewFixedValue returns a flag value which stores its value in *p in baseUnits. A non-zero dim
+// is the dimension required of the flag's quantity. The default value of the flag is the value of *p, i.e:
+//
+//	current, _ := si.NewDimension(0, 0, 0, 0, 1, 0, 0)
+//	v := int64(100) // 100mA
+//	fs.Var(si.NewFixedValue(&v, si.PrefixMilli, current), "current", "output current")
+func NewFixedValue(p *int64, baseUnits Prefix, dim Dimension) *FixedValue {
+	return &FixedValue{p: p, base: baseUnits, dim: dim}
+}
+
+// FixedFlag defines a dimensionless fixed-point flag with the specified name, base units,
+// default value and usage string on the [flag.CommandLine]. The default value is parsed as in
+// [FixedValue.Set] and FixedFlag panics if it is not valid. The flag's value is stored in *p.
+//
+//	var timeout int64
+//	si.FixedFlag(&timeout, "timeout", si.PrefixMilli, "100m", "timeout in seconds")
+func FixedFlag(p *int64, name string, baseUnits Prefix, value, usage string) {
+	fixedVar(flag.CommandLine, p, name, baseUnits, Dimension{}, value, usage)
+}
+
+// FixedDimFlag is like [FixedFlag] but the flag is a quantity of dimension dim, i.e: "--current=2.5mA".
+func FixedDimFlag(p *int64, name string, baseUnits Prefix, dim Dimension, value, usage string) {
+	fixedVar(flag.CommandLine, p, name, baseUnits, dim, value, usage)
+}
+
+func fixedVar(fs *flag.FlagSet, p *int64, name string, baseUnits Prefix, dim Dimension, value, usage string) {
+	v := NewFixedValue(p, baseUnits, dim)
+	if err := v.Set(value); err != nil {
+		panic(fmt.Sprintf("si: invalid default value %q for flag -%s: %v", value, name, err))
+	}
+	fs.Var(v, name, usage)
+}
+
+// Set implements [flag.Value]. The quantity's dimension must match the dimension of the flag,
+// otherwise s is parsed as in [ParseFixed]. All of s must be consumed.
+func (v *FixedValue) Set(s string) error {
+	q, n, err := ParseQuantity(s, v.base)
+	if err == nil && n == len(s) && q.dim == v.dim {
+		*v.p = q.value
+		return nil
+	}
+	fixed, nf, ferr := ParseFixed(s, v.base)
+	if ferr == nil && nf == len(s) {
+		*v.p = fixed
+		return nil
+	}
+	switch {
+	case err == nil && n == len(s):
+		return fmt.Errorf("%w: want %s, got %s", ErrDimMismatch, unitString(v.dim), unitString(q.dim))
+	case ferr != nil:
+		return ferr
+	}
+	return ErrUnknownPrefix.at(s, nf)
+}
+
+// String implements [flag.Value]. The value is formatted as in [Quantity.String] except that dimensionless
+// values are always formatted with an SI prefix, i.e: "100m", since [FixedValue.Set] reads them as in [ParseFixed].
+func (v *FixedValue) String() string {
+	if v.p == nil {
+		return "0" // Zero value used by the flag package to detect default values.
+	} else if v.dim.IsDimensionless() {
+		return string(FixedFormat{Fmt: 'g', Prec: fixedDigits(*v.p), stripZeros: true}.Append(nil, *v.p, v.base))
+	}
+	return v.Quantity().String()
+}
+
+// Get implements [flag.Getter] and returns the int64 fixed-point value.
+func (v *FixedValue) Get() any { return *v.p }
+
+// Type returns the flag's SI unit, i.e: "A" or "Hz", or "fixed" if the flag is dimensionless.
+// It is used by the pflag package to print the flag's usage.
+func (v *FixedValue) Type() string {
+	if v.dim.IsDimensionless() {
+		return "fixed"
+	}
+	return unitString(v.dim)
+}
+
+// Quantity returns the flag's value as a [Quantity].
+func (v *FixedValue) Quantity() Quantity {
+	return Quantity{value: *v.p, base: v.base, dim: v.dim}
+}
+
+// unitString returns the SI unit of dim for error and usage messages.
+func unitString(dim Dimension) string {
+	if dim.IsDimensionless() {
+		return "dimensionless"
+	}
+	return siDimFormatter.StringDim(dim)
+}
@@ -0,0 +1,118 @@
+package si
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFixedValueSet(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	length := newdim([]int{1, 0, 0, 0, 0, 0, 0})
+	var tests = []struct {
+		S     string
+		BaseU Prefix
+		Dim   Dimension
+		Want  int64
+	}{
+		0: {S: "2.5m", BaseU: PrefixMicro, Dim: current, Want: 2500},
+		1: {S: "2.5mA", BaseU: PrefixMicro, Dim: current, Want: 2500},
+		2: {S: "2.5 mA", BaseU: PrefixMicro, Dim: current, Want: 2500},
+		3: {S: "3A", BaseU: PrefixMilli, Dim: current, Want: 3000},
+		4: {S: "1.2G", BaseU: PrefixKilo, Dim: newdim([]int{0, 0, -1, 0, 0, 0, 0}), Want: 1_200_000},
+		5: {S: "1.2GHz", BaseU: PrefixKilo, Dim: newdim([]int{0, 0, -1, 0, 0, 0, 0}), Want: 1_200_000},
+		6: {S: "100m", BaseU: PrefixMilli, Want: 100},
+		7: {S: "-1e3", BaseU: PrefixNone, Want: -1000},
+		// Units take precedence over prefixes.
+		8:  {S: "5m", BaseU: PrefixMilli, Dim: length, Want: 5000},
+		9:  {S: "5mm", BaseU: PrefixMilli, Dim: length, Want: 5},
+		10: {S: "5k", BaseU: PrefixNone, Dim: length, Want: 5000},
+	}
+	for i, test := range tests {
+		var got int64
+		v := NewFixedValue(&got, test.BaseU, test.Dim)
+		err := v.Set(test.S)
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		if got != test.Want || v.Get() != test.Want {
+			t.Errorf("case %d: want %d, got %d from %q", i, test.Want, got, test.S)
+		}
+		// Formatted value round trips.
+		var back int64
+		err = NewFixedValue(&back, test.BaseU, test.Dim).Set(v.String())
+		if err != nil || back != got {
+			t.Errorf("case %d: round trip of %q got %d (%v)", i, v.String(), back, err)
+		}
+	}
+	var tests2 = []struct {
+		S     string
+		BaseU Prefix
+		Dim   Dimension
+		Err   error
+	}{
+		0: {S: "2.5mV", BaseU: PrefixMilli, Dim: current, Err: ErrDimMismatch},
+		1: {S: "2.5mA", BaseU: PrefixMilli, Err: ErrDimMismatch},
+		2: {S: "2.5x", BaseU: PrefixMilli, Err: ErrUnknownPrefix},
+		3: {S: "", BaseU: PrefixMilli, Dim: current, Err: ErrNaN},
+		4: {S: "1e20", BaseU: PrefixMilli, Err: ErrOverflowsInt64},
+		5: {S: "1", BaseU: 4, Err: ErrInvalidPrefix},
+	}
+	for i, test := range tests2 {
+		got := int64(1)
+		err := NewFixedValue(&got, test.BaseU, test.Dim).Set(test.S)
+		if !errors.Is(err, test.Err) {
+			t.Errorf("error case %d: want %v, got %v", i, test.Err, err)
+		}
+		if got != 1 {
+			t.Errorf("error case %d: value modified on error", i)
+		}
+	}
+}
+
+func TestFixedFlag(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var timeout, amps, zero int64
+	fixedVar(fs, &timeout, "timeout", PrefixMilli, Dimension{}, "100m", "timeout in seconds")
+	fixedVar(fs, &amps, "current", PrefixMicro, current, "2.5mA", "output current")
+	fixedVar(fs, &zero, "zero", PrefixMilli, Dimension{}, "0", "zero default")
+	if timeout != 100 || amps != 2500 {
+		t.Fatal("defaults not set", timeout, amps)
+	}
+	var usage strings.Builder
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	fs.SetOutput(io.Discard)
+	for _, want := range []string{"(default 100m)", "(default 2.5mA)", "zero default\n"} {
+		if !strings.Contains(usage.String(), want) {
+			t.Errorf("usage missing %q:\n%s", want, usage.String())
+		}
+	}
+	err := fs.Parse([]string{"-timeout=1.5", "--current", "3m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeout != 1500 || amps != 3000 {
+		t.Error("unexpected parsed values", timeout, amps)
+	}
+	if err = fs.Parse([]string{"-current=3mV"}); err == nil {
+		t.Error("expected error for dimension mismatch")
+	}
+	if typ := fs.Lookup("current").Value.(*FixedValue).Type(); typ != "A" {
+		t.Error("unexpected type", typ)
+	}
+	if typ := fs.Lookup("timeout").Value.(*FixedValue).Type(); typ != "fixed" {
+		t.Error("unexpected type", typ)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid default")
+		}
+	}()
+	fixedVar(fs, &zero, "bad", PrefixMilli, current, "3mV", "")
+}
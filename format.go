@@ -0,0 +1,326 @@
+package si
+
+import (
+	"errors"
+	"strconv"
+)
+
+// FixedFormat holds the options for formatting fixed-point numbers.
+// Formatting with only Fmt and Prec set is equivalent to calling [AppendFixed].
+type FixedFormat struct {
+	// Fmt is the format verb:
+	//   - 'f' formats the value with an SI prefix, i.e: "1.234M".
+	//   - 'e' formats the value in scientific notation, i.e: "1.234e+06".
+	//   - 'g' uses 'f' for values within the [PrefixAtto] to [PrefixExa] range and 'e' otherwise.
+	Fmt byte
+	// Prec is the amount of significant digits formatted. Values of at most 3 digits in their
+	// base units are not rounded and are formatted with all their digits unless StrictPrec is set.
+	// If DecimalPlaces is set Prec is instead the amount of digits after the decimal point.
+	Prec int
+	// DecimalPlaces formats Prec digits after the decimal point of the value in the prefix
+	// it is formatted with, or of the mantissa in scientific notation. Prec may then be zero.
+	// i.e: "12.346m" and "1.235k" for Prec=3.
+	DecimalPlaces bool
+	// KeepZeros pads the decimal part with trailing zeros up to the precision, i.e: "12.000m"
+	// with DecimalPlaces and Prec=3 or "1.00k" for Prec=3. Otherwise the digits of the value are printed
+	// up to the precision, so 3300 milli is "3.300" for Prec=4, and a decimal part of only zeros is omitted.
+	KeepZeros bool
+	// NonEngineering enables formatting with the centi, deci, deca and hecto prefixes.
+	// Values are then formatted with the largest prefix that gives a non-zero integer part,
+	// i.e: "2.5c" instead of "25m" and "7.50h" instead of "750" for Prec=3.
+	NonEngineering bool
+	// Rounding is the rounding mode used to discard digits. The zero value rounds half away from zero.
+	Rounding RoundingMode
+	// StrictPrec rounds values of at most 3 digits in their base units to Prec significant digits too,
+	// i.e: "1" instead of "999m" for 999 milli and Prec=1. Use DecimalPlaces to format all integer digits.
+	StrictPrec bool
+	// FixedPrefix formats values in units of Prefix instead of choosing the prefix from their magnitude,
+	// i.e: "0.012k" or "12000m" for 12. The 'g' verb then behaves as 'f' and 'e' is not affected.
+	FixedPrefix bool
+	// Prefix is the prefix values are formatted with if FixedPrefix is set.
+	Prefix Prefix
+
+	// stripZeros omits all trailing zeros of the decimal part, as done by [Quantity.AppendFormat] with a negative precision.
+	stripZeros bool
+}
+
+// Formatting errors returned by [AppendFixedErr] and [FixedFormat.AppendErr].
+var (
+	ErrInvalidFormat     = errors.New("invalid format verb")
+	ErrPrecisionTooSmall = errors.New("precision must be greater than zero")
+	ErrPrecisionTooLarge = errors.New("precision too large")
+	ErrInvalidBase       = errors.New("invalid base units prefix")
+	ErrUnrepresentable   = errors.New("value exceeds largest SI prefix")
+)
+
+// Append formats value expressed in baseUnits according to f and appends it to b.
+// Errors are written to b as in [AppendFixed].
+func (f FixedFormat) Append(b []byte, value int64, baseUnits Prefix) []byte {
+	res, err := f.AppendErr(b, value, baseUnits)
+	if err != nil {
+		return append(b, formatErrorString(err)...)
+	}
+	return res
+}
+
+// AppendErr formats value expressed in baseUnits according to f and appends it to b.
+// On error b is returned unmodified together with one of the formatting errors.
+func (f FixedFormat) AppendErr(b []byte, value int64, baseUnits Prefix) ([]byte, error) {
+	u, neg := uabs(value)
+	return f.appendErr(b, 0, u, neg, baseUnits, 20)
+}
+
+// appendErr formats the 128-bit magnitude hi:lo with sign neg expressed in baseUnits and
+// appends it to b. maxPrec is the largest precision accepted.
+func (f FixedFormat) appendErr(b []byte, hi, lo uint64, neg bool, baseUnits Prefix, maxPrec int) ([]byte, error) {
+	switch {
+	case f.Fmt != 'f' && f.Fmt != 'e' && f.Fmt != 'g':
+		return b, ErrInvalidFormat
+	case f.Prec < 0 || f.Prec == 0 && !f.DecimalPlaces:
+		return b, ErrPrecisionTooSmall
+	case !baseUnits.IsValid():
+		return b, ErrInvalidBase
+	case f.Prec > maxPrec:
+		return b, ErrPrecisionTooLarge
+	case !f.Rounding.IsValid():
+		return b, ErrInvalidRoundingMode
+	case f.FixedPrefix && !f.Prefix.IsValid():
+		return b, ErrInvalidPrefix
+	case hi == 0 && lo == 0 && f.Fmt == 'e':
+		return f.appendExp(b, &fixedDecimal{}), nil
+	case hi == 0 && lo == 0:
+		pfx := f.prefixFor(0)
+		return f.appendFixed(b, &fixedDecimal{lead: pfx.Exponent()}, pfx), nil
+	}
+	var buf [39]byte // Up to 39 digits of a 128-bit magnitude.
+	dd := fixedDecimal{
+		digits: appendUint128(buf[:0], hi, lo),
+		neg:    neg,
+	}
+	dd.lead = len(dd.digits) - 1 + baseUnits.Exponent()
+	switch f.Fmt {
+	case 'e':
+		return f.appendExp(b, &dd), nil
+	case 'g':
+		var fbuf [39]byte
+		fd := dd
+		fd.digits = append(fbuf[:0], dd.digits...)
+		if pfx := f.roundFixed(&fd); f.FixedPrefix || pfx >= PrefixAtto && pfx <= PrefixExa {
+			return f.appendFixed(b, &fd, pfx), nil
+		}
+		return f.appendExp(b, &dd), nil
+	}
+	pfx := f.roundFixed(&dd)
+	if pfx > PrefixQuetta {
+		return b, ErrUnrepresentable
+	}
+	return f.appendFixed(b, &dd, pfx), nil
+}
+
+// roundFixed rounds d for formatting with the 'f' verb and returns the prefix it is formatted with.
+func (f FixedFormat) roundFixed(d *fixedDecimal) Prefix {
+	if f.DecimalPlaces {
+		pfx := f.prefixFor(d.lead)
+		d.round(d.lead-pfx.Exponent()+1+f.Prec, f.Rounding)
+	} else if f.StrictPrec || len(d.digits) > 3 {
+		d.round(f.Prec, f.Rounding)
+	}
+	// Rounding may carry over to a larger prefix, i.e: 999.9 -> 1k.
+	return f.prefixFor(d.lead)
+}
+
+// appendFixed appends d, rounded by roundFixed, in units of pfx to b.
+func (f FixedFormat) appendFixed(b []byte, d *fixedDecimal, pfx Prefix) []byte {
+	minDecimals := 0
+	if f.KeepZeros && f.DecimalPlaces {
+		minDecimals = f.Prec
+	} else if f.KeepZeros {
+		minDecimals = f.Prec - (d.lead - pfx.Exponent() + 1)
+	}
+	return d.appendFixed(b, pfx, minDecimals, f.stripZeros)
+}
+
+// appendExp rounds d and appends it in scientific notation to b.
+func (f FixedFormat) appendExp(b []byte, d *fixedDecimal) []byte {
+	n := f.Prec
+	if f.DecimalPlaces {
+		n++ // Mantissa has a single integer digit.
+	}
+	d.round(n, f.Rounding)
+	minDecimals := 0
+	if f.KeepZeros {
+		minDecimals = n - 1
+	}
+	return d.appendExp(b, minDecimals)
+}
+
+// prefixFor returns the prefix a value with a leading digit of exponent lead is formatted with.
+func (f FixedFormat) prefixFor(lead int) Prefix {
+	if f.FixedPrefix {
+		return f.Prefix
+	} else if f.NonEngineering && lead >= int(PrefixCenti) && lead <= int(PrefixHecto) {
+		return Prefix(lead)
+	}
+	return Prefix(lead - mod3(lead))
+}
+
+// mod3 returns the non-negative remainder of a divided by 3.
+func mod3(a int) int {
+	m := a % 3
+	if m < 0 {
+		m += 3
+	}
+	return m
+}
+
+// formatErrorString returns the string written to the buffer on a formatting error.
+func formatErrorString(err error) string {
+	switch err {
+	case ErrInvalidFormat:
+		return "<si!INVALID FMT>"
+	case ErrPrecisionTooSmall:
+		return "<si!LESS-EQ-ZERO PREC>"
+	case ErrInvalidBase:
+		return "<si!BAD BASE>"
+	case ErrPrecisionTooLarge:
+		return "<si!LARGE PREC>"
+	case ErrUnrepresentable:
+		return "<si!UNREPRESENTABLE PREFIX>"
+	case ErrInvalidRoundingMode:
+		return "<si!BAD ROUNDING>"
+	case ErrInvalidPrefix:
+		return "<si!BAD PREFIX>"
+	}
+	return "<si!" + err.Error() + ">"
+}
+
+// fixedDecimal is the base 10 digit representation of a fixed-point value.
+type fixedDecimal struct {
+	// digits holds the significant digits in ASCII, most significant first.
+	// Trailing zeros are not significant. Empty digits represent zero.
+	digits []byte
+	// lead is the power of ten of the first digit.
+	lead int
+	neg  bool
+}
+
+// round rounds d to n significant digits with mode.
+// If n is zero or negative d is rounded to a multiple of 10^(lead+1-n).
+func (d *fixedDecimal) round(n int, mode RoundingMode) {
+	if n >= len(d.digits) {
+		return
+	} else if n < 0 {
+		// Less than half of rounding unit, only directed rounding rounds up.
+		if mode.roundUp(false, 0, true, d.neg) {
+			d.digits = append(d.digits[:0], '1')
+			d.lead += 1 - n
+		} else {
+			d.digits = d.digits[:0]
+		}
+		return
+	}
+	sticky := false
+	for _, c := range d.digits[n+1:] {
+		sticky = sticky || c != '0'
+	}
+	odd := n > 0 && (d.digits[n-1]-'0')&1 == 1
+	roundUp := mode.roundUp(odd, d.digits[n]-'0', sticky, d.neg)
+	d.digits = d.digits[:n]
+	if !roundUp {
+		return
+	}
+	for i := n - 1; i >= 0; i-- {
+		if d.digits[i] < '9' {
+			d.digits[i]++
+			return
+		}
+		d.digits[i] = '0'
+	}
+	// Carry over leading digit, i.e: 999 -> 1000.
+	d.digits = append(d.digits[:0], '1')
+	d.lead++
+}
+
+// digitAt returns the ASCII digit of d at the power of ten exp.
+func (d *fixedDecimal) digitAt(exp int) byte {
+	i := d.lead - exp
+	if i < 0 || i >= len(d.digits) {
+		return '0'
+	}
+	return d.digits[i]
+}
+
+// trimZeros removes the trailing zeros of d's digits.
+func (d *fixedDecimal) trimZeros() {
+	for len(d.digits) > 0 && d.digits[len(d.digits)-1] == '0' {
+		d.digits = d.digits[:len(d.digits)-1]
+	}
+}
+
+// appendExp appends d in scientific notation to b, i.e: "1.234e+06". Trailing zeros of the mantissa
+// are not printed beyond minDecimals digits after the decimal point.
+func (d *fixedDecimal) appendExp(b []byte, minDecimals int) []byte {
+	d.trimZeros()
+	if len(d.digits) == 0 {
+		d.lead = 0
+	} else if d.neg {
+		b = append(b, '-')
+	}
+	b = append(b, d.digitAt(d.lead))
+	decimals := len(d.digits) - 1
+	if minDecimals > decimals {
+		decimals = minDecimals
+	}
+	if decimals > 0 {
+		b = append(b, '.')
+		for pos := d.lead - 1; pos >= d.lead-decimals; pos-- {
+			b = append(b, d.digitAt(pos))
+		}
+	}
+	exp := d.lead
+	if exp < 0 {
+		b = append(b, "e-"...)
+		exp = -exp
+	} else {
+		b = append(b, "e+"...)
+	}
+	if exp < 10 {
+		b = append(b, '0')
+	}
+	return strconv.AppendInt(b, int64(exp), 10)
+}
+
+// appendFixed appends d formatted in units of pfx to b. A decimal part of only zeros is not printed
+// beyond minDecimals digits after the decimal point and neither are any trailing zeros of the decimal part if strip is set.
+func (d *fixedDecimal) appendFixed(b []byte, pfx Prefix, minDecimals int, strip bool) []byte {
+	n := len(d.digits)
+	d.trimZeros()
+	if d.neg && len(d.digits) > 0 {
+		b = append(b, '-')
+	}
+	exp := pfx.Exponent()
+	if !strip && d.lead-len(d.digits)+1 < exp {
+		d.digits = d.digits[:n] // Keep trailing zeros of non-zero decimal part.
+	}
+	if len(d.digits) == 0 || d.lead < exp {
+		b = append(b, '0')
+	} else {
+		for pos := d.lead; pos >= exp; pos-- {
+			b = append(b, d.digitAt(pos))
+		}
+	}
+	decimals := minDecimals
+	if last := d.lead - len(d.digits) + 1; len(d.digits) > 0 && exp-last > decimals {
+		decimals = exp - last
+	}
+	if decimals > 0 {
+		b = append(b, '.')
+		for pos := exp - 1; pos >= exp-decimals; pos-- {
+			b = append(b, d.digitAt(pos))
+		}
+	}
+	if pfx != PrefixNone {
+		b = append(b, pfx.String()...)
+	}
+	return b
+}
@@ -0,0 +1,257 @@
+package si
+
+import "testing"
+
+func TestFixedFormatNonEngineering(t *testing.T) {
+	var tests = []struct {
+		V     int64
+		BaseU Prefix
+		Prec  int
+		Want  string
+	}{
+		0: {V: 50, BaseU: PrefixMilli, Prec: 3, Want: "5c"},
+		1: {V: 500, BaseU: PrefixMilli, Prec: 3, Want: "5d"},
+		2: {V: 50, BaseU: PrefixNone, Prec: 3, Want: "5da"},
+		3: {V: 500, BaseU: PrefixNone, Prec: 3, Want: "5h"},
+		4: {V: 25, BaseU: PrefixMilli, Prec: 3, Want: "2.5c"},
+		5: {V: 750, BaseU: PrefixNone, Prec: 3, Want: "7.50h"},
+		// Outside of centi to hecto engineering prefixes are used.
+		6: {V: 5, BaseU: PrefixMilli, Prec: 3, Want: "5m"},
+		7: {V: 5000, BaseU: PrefixNone, Prec: 3, Want: "5k"},
+		8: {V: 5, BaseU: PrefixNone, Prec: 3, Want: "5"},
+		// Rounding carries over to next prefix.
+		9:  {V: 9_996, BaseU: PrefixMicro, Prec: 2, Want: "1c"},
+		10: {V: 99_600, BaseU: PrefixMicro, Prec: 1, Want: "1d"},
+	}
+	f := FixedFormat{Fmt: 'f', NonEngineering: true}
+	var buf [32]byte
+	for i, test := range tests {
+		f.Prec = test.Prec
+		got := f.Append(buf[:0], test.V, test.BaseU)
+		if string(got) != test.Want {
+			t.Errorf("case %d: want %s, got %s", i, test.Want, got)
+		}
+		got = f.Append(buf[:0], -test.V, test.BaseU)
+		if string(got) != "-"+test.Want {
+			t.Errorf("case %d: want -%s, got %s", i, test.Want, got)
+		}
+		_, n, err := ParseFixed(test.Want, test.BaseU)
+		if err != nil || n != len(test.Want) {
+			t.Errorf("case %d: parse %q: %v", i, test.Want, err)
+		}
+	}
+}
+
+func TestAppendFixedVerbs(t *testing.T) {
+	var tests = []struct {
+		V     int64
+		BaseU Prefix
+		Fmt   byte
+		Prec  int
+		Want  string
+	}{
+		// Scientific notation.
+		0: {V: 1_234_000, BaseU: PrefixNone, Fmt: 'e', Prec: 4, Want: "1.234e+06"},
+		1: {V: 1_234_567, BaseU: PrefixNone, Fmt: 'e', Prec: 4, Want: "1.235e+06"},
+		2: {V: 1_000_000, BaseU: PrefixNone, Fmt: 'e', Prec: 4, Want: "1e+06"},
+		3: {V: 25, BaseU: PrefixMilli, Fmt: 'e', Prec: 3, Want: "2.5e-02"},
+		4: {V: 5, BaseU: PrefixNone, Fmt: 'e', Prec: 3, Want: "5e+00"},
+		5: {V: 999_999, BaseU: PrefixQuecto, Fmt: 'e', Prec: 2, Want: "1e-24"},
+		6: {V: 123_456, BaseU: PrefixQuetta, Fmt: 'e', Prec: 20, Want: "1.23456e+35"},
+		7: {V: 123_456, BaseU: PrefixNone, Fmt: 'e', Prec: 1, Want: "1e+05"},
+		// Prefix notation within atto to exa range.
+		8:  {V: 1_234_000, BaseU: PrefixNone, Fmt: 'g', Prec: 4, Want: "1.234M"},
+		9:  {V: 1, BaseU: PrefixAtto, Fmt: 'g', Prec: 4, Want: "1a"},
+		10: {V: 999_999, BaseU: PrefixTera, Fmt: 'g', Prec: 3, Want: "1E"},
+		11: {V: 999_999, BaseU: PrefixYocto, Fmt: 'g', Prec: 3, Want: "1a"},
+		// Exponent notation outside of atto to exa range.
+		12: {V: 1234, BaseU: PrefixExa, Fmt: 'g', Prec: 3, Want: "1.23e+21"},
+		13: {V: 999_999, BaseU: PrefixExa, Fmt: 'g', Prec: 3, Want: "1e+24"},
+		14: {V: 5, BaseU: PrefixZepto, Fmt: 'g', Prec: 3, Want: "5e-21"},
+		15: {V: 1234, BaseU: PrefixQuetta, Fmt: 'g', Prec: 3, Want: "1.23e+33"},
+	}
+	var buf [32]byte
+	for i, test := range tests {
+		got := AppendFixed(buf[:0], test.V, test.BaseU, test.Fmt, test.Prec)
+		if string(got) != test.Want {
+			t.Errorf("case %d: want %s, got %s", i, test.Want, got)
+		}
+		got = AppendFixed(buf[:0], -test.V, test.BaseU, test.Fmt, test.Prec)
+		if string(got) != "-"+test.Want {
+			t.Errorf("case %d: want -%s, got %s", i, test.Want, got)
+		}
+	}
+	if got := AppendFixed(buf[:0], 0, PrefixNone, 'e', 3); string(got) != "0e+00" {
+		t.Errorf("want 0e+00, got %s", got)
+	}
+	if got := AppendFixed(buf[:0], 0, PrefixNone, 'g', 3); string(got) != "0" {
+		t.Errorf("want 0, got %s", got)
+	}
+	if got := AppendFixed(buf[:0], 1, PrefixNone, 'x', 3); got[0] != '<' {
+		t.Errorf("expected error for invalid verb, got %s", got)
+	}
+}
+
+func TestFixedFormatDecimalPlaces(t *testing.T) {
+	var tests = []struct {
+		V         int64
+		BaseU     Prefix
+		Fmt       byte
+		Prec      int
+		Decimals  bool
+		KeepZeros bool
+		Want      string
+	}{
+		0: {V: 12_300, BaseU: PrefixMicro, Fmt: 'f', Prec: 3, Decimals: true, KeepZeros: true, Want: "12.300m"},
+		1: {V: 12_300, BaseU: PrefixMicro, Fmt: 'f', Prec: 3, Decimals: true, Want: "12.300m"},
+		2: {V: 12_345_678, BaseU: PrefixMicro, Fmt: 'f', Prec: 3, Decimals: true, Want: "12.346"},
+		3: {V: 1_234_567, BaseU: PrefixNone, Fmt: 'f', Prec: 1, Decimals: true, Want: "1.2M"},
+		4: {V: 5, BaseU: PrefixNone, Fmt: 'f', Prec: 0, Decimals: true, Want: "5"},
+		5: {V: 5, BaseU: PrefixNone, Fmt: 'f', Prec: 2, Decimals: true, KeepZeros: true, Want: "5.00"},
+		6: {V: 1500, BaseU: PrefixMilli, Fmt: 'f', Prec: 0, Decimals: true, Want: "2"},
+		7: {V: 5, BaseU: PrefixMilli, Fmt: 'f', Prec: 2, Decimals: true, KeepZeros: true, Want: "5.00m"},
+		// Rounding carries over to next prefix.
+		8: {V: 999_960, BaseU: PrefixMilli, Fmt: 'f', Prec: 1, Decimals: true, Want: "1k"},
+		9: {V: 999_960, BaseU: PrefixMilli, Fmt: 'f', Prec: 1, Decimals: true, KeepZeros: true, Want: "1.0k"},
+		// Trailing zeros kept up to significant digits.
+		10: {V: 12, BaseU: PrefixNone, Fmt: 'f', Prec: 3, KeepZeros: true, Want: "12.0"},
+		11: {V: 1000, BaseU: PrefixNone, Fmt: 'f', Prec: 3, KeepZeros: true, Want: "1.00k"},
+		12: {V: 123_456, BaseU: PrefixNone, Fmt: 'f', Prec: 2, KeepZeros: true, Want: "120k"},
+		13: {V: 1, BaseU: PrefixNano, Fmt: 'g', Prec: 4, KeepZeros: true, Want: "1.000n"},
+		// Scientific notation.
+		14: {V: 1_234_567, BaseU: PrefixNone, Fmt: 'e', Prec: 2, Decimals: true, Want: "1.23e+06"},
+		15: {V: 1_000_000, BaseU: PrefixNone, Fmt: 'e', Prec: 3, KeepZeros: true, Want: "1.00e+06"},
+		16: {V: 1_000_000, BaseU: PrefixNone, Fmt: 'e', Prec: 3, Decimals: true, KeepZeros: true, Want: "1.000e+06"},
+		17: {V: 1, BaseU: PrefixQuetta, Fmt: 'g', Prec: 1, Decimals: true, KeepZeros: true, Want: "1.0e+30"},
+		// Decimal part of only zeros is omitted unless KeepZeros is set.
+		18: {V: 12_000, BaseU: PrefixMicro, Fmt: 'f', Prec: 3, Decimals: true, Want: "12m"},
+		// Integer digits are kept, unlike with significant digits.
+		19: {V: 987_654, BaseU: PrefixNone, Fmt: 'f', Prec: 0, Decimals: true, Want: "988k"},
+	}
+	var buf [32]byte
+	for i, test := range tests {
+		f := FixedFormat{Fmt: test.Fmt, Prec: test.Prec, DecimalPlaces: test.Decimals, KeepZeros: test.KeepZeros}
+		got, err := f.AppendErr(buf[:0], test.V, test.BaseU)
+		if err != nil || string(got) != test.Want {
+			t.Errorf("case %d: want %s, got %s (%v)", i, test.Want, got, err)
+		}
+		got, err = f.AppendErr(buf[:0], -test.V, test.BaseU)
+		if err != nil || string(got) != "-"+test.Want {
+			t.Errorf("case %d: want -%s, got %s (%v)", i, test.Want, got, err)
+		}
+	}
+	for i, test := range []struct {
+		F    FixedFormat
+		Want string
+	}{
+		0: {F: FixedFormat{Fmt: 'f', Prec: 3, DecimalPlaces: true, KeepZeros: true}, Want: "0.000"},
+		1: {F: FixedFormat{Fmt: 'f', Prec: 3, KeepZeros: true}, Want: "0.00"},
+		2: {F: FixedFormat{Fmt: 'e', Prec: 3, KeepZeros: true}, Want: "0.00e+00"},
+		3: {F: FixedFormat{Fmt: 'f', Prec: 0, DecimalPlaces: true, KeepZeros: true}, Want: "0"},
+	} {
+		if got := test.F.Append(buf[:0], 0, PrefixMilli); string(got) != test.Want {
+			t.Errorf("zero case %d: want %s, got %s", i, test.Want, got)
+		}
+	}
+	if _, err := (FixedFormat{Fmt: 'f', Prec: -1, DecimalPlaces: true}).AppendErr(buf[:0], 1, PrefixNone); err != ErrPrecisionTooSmall {
+		t.Error("expected precision error, got", err)
+	}
+	if _, err := (FixedFormat{Fmt: 'f', Prec: 0}).AppendErr(buf[:0], 1, PrefixNone); err != ErrPrecisionTooSmall {
+		t.Error("expected precision error, got", err)
+	}
+}
+
+func TestFixedFormatPrefix(t *testing.T) {
+	var tests = []struct {
+		V     int64
+		BaseU Prefix
+		F     FixedFormat
+		Want  string
+	}{
+		0: {V: 12, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixKilo}, Want: "0.012k"},
+		1: {V: 12, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixMilli}, Want: "12000m"},
+		2: {V: 12_345, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 2, Prefix: PrefixKilo}, Want: "12k"},
+		3: {V: 12_345, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 2, Prefix: PrefixMega}, Want: "0.012M"},
+		4: {V: 12_345, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 2, Prefix: PrefixMilli}, Want: "12000000m"},
+		5: {V: 12, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixKilo, KeepZeros: true}, Want: "0.0120k"},
+		6: {V: 1, BaseU: PrefixKilo, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixNano}, Want: "1000000000000n"},
+		7: {V: 250, BaseU: PrefixMilli, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixCenti}, Want: "25c"},
+		8: {V: 1234, BaseU: PrefixExa, F: FixedFormat{Fmt: 'g', Prec: 3, Prefix: PrefixExa}, Want: "1230E"},
+		// Rounding does not carry over to the next prefix.
+		9:  {V: 999_999, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixKilo}, Want: "1000k"},
+		10: {V: 999_999, BaseU: PrefixNone, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixKilo, Rounding: RoundTowardZero}, Want: "999k"},
+		// Decimal places in the output prefix.
+		11: {V: 12_300, BaseU: PrefixMicro, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixMilli, DecimalPlaces: true, KeepZeros: true}, Want: "12.300m"},
+		12: {V: 1_500_000, BaseU: PrefixMicro, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixMilli, DecimalPlaces: true, KeepZeros: true}, Want: "1500.000m"},
+		13: {V: 500, BaseU: PrefixMilli, F: FixedFormat{Fmt: 'f', Prec: 3, Prefix: PrefixKilo, DecimalPlaces: true}, Want: "0.001k"},
+		14: {V: 12_345, BaseU: PrefixMilli, F: FixedFormat{Fmt: 'f', Prec: 1, Prefix: PrefixNone, DecimalPlaces: true}, Want: "12.3"},
+		// Scientific notation is not affected.
+		15: {V: 12, BaseU: PrefixNone, F: FixedFormat{Fmt: 'e', Prec: 2, Prefix: PrefixKilo}, Want: "1.2e+01"},
+	}
+	var buf [32]byte
+	for i, test := range tests {
+		test.F.FixedPrefix = true
+		got, err := test.F.AppendErr(buf[:0], test.V, test.BaseU)
+		if err != nil || string(got) != test.Want {
+			t.Errorf("case %d: want %s, got %s (%v)", i, test.Want, got, err)
+		}
+		got, err = test.F.AppendErr(buf[:0], -test.V, test.BaseU)
+		if err != nil || string(got) != "-"+test.Want {
+			t.Errorf("case %d: want -%s, got %s (%v)", i, test.Want, got, err)
+		}
+	}
+	// Values smaller than the last decimal place.
+	for i, test := range []struct {
+		V    int64
+		F    FixedFormat
+		Want string
+	}{
+		0: {V: 4, F: FixedFormat{Fmt: 'f', Prec: 3, DecimalPlaces: true}, Want: "0k"},
+		1: {V: -4, F: FixedFormat{Fmt: 'f', Prec: 3, DecimalPlaces: true, KeepZeros: true}, Want: "0.000k"},
+		2: {V: 4, F: FixedFormat{Fmt: 'f', Prec: 3, DecimalPlaces: true, Rounding: RoundCeil}, Want: "0.001k"},
+		3: {V: -4, F: FixedFormat{Fmt: 'f', Prec: 3, DecimalPlaces: true, Rounding: RoundFloor}, Want: "-0.001k"},
+		4: {V: 0, F: FixedFormat{Fmt: 'f', Prec: 3, KeepZeros: true}, Want: "0.00k"},
+		5: {V: 0, F: FixedFormat{Fmt: 'f', Prec: 2, DecimalPlaces: true, KeepZeros: true}, Want: "0.00k"},
+	} {
+		test.F.FixedPrefix = true
+		test.F.Prefix = PrefixKilo
+		if got := test.F.Append(buf[:0], test.V, PrefixMilli); string(got) != test.Want {
+			t.Errorf("small case %d: want %s, got %s", i, test.Want, got)
+		}
+	}
+	got := FixedFormat{Fmt: 'f', Prec: 3, FixedPrefix: true, Prefix: 4}.Append(buf[:0], 1, PrefixNone)
+	if string(got) != "<si!BAD PREFIX>" {
+		t.Error("expected invalid prefix error, got", string(got))
+	}
+}
+
+func TestFixedFormatStrictPrec(t *testing.T) {
+	var tests = []struct {
+		V        int64
+		BaseU    Prefix
+		Prec     int
+		Rounding RoundingMode
+		Want     string
+	}{
+		0: {V: 999, BaseU: PrefixMilli, Prec: 1, Want: "1"},
+		1: {V: 999, BaseU: PrefixMilli, Prec: 1, Rounding: RoundTowardZero, Want: "900m"},
+		2: {V: 125, BaseU: PrefixNone, Prec: 2, Want: "130"},
+		3: {V: 125, BaseU: PrefixNone, Prec: 2, Rounding: RoundHalfEven, Want: "120"},
+		4: {V: 987, BaseU: PrefixNone, Prec: 1, Want: "1k"},
+		// Values with more than 3 digits are rounded as without StrictPrec.
+		5: {V: 987_654, BaseU: PrefixNone, Prec: 1, Want: "1M"},
+		6: {V: 12, BaseU: PrefixNone, Prec: 3, Want: "12"},
+	}
+	var buf [32]byte
+	for i, test := range tests {
+		f := FixedFormat{Fmt: 'f', Prec: test.Prec, Rounding: test.Rounding, StrictPrec: true}
+		got, err := f.AppendErr(buf[:0], test.V, test.BaseU)
+		if err != nil || string(got) != test.Want {
+			t.Errorf("case %d: want %s, got %s (%v)", i, test.Want, got, err)
+		}
+		got, err = f.AppendErr(buf[:0], -test.V, test.BaseU)
+		if err != nil || string(got) != "-"+test.Want {
+			t.Errorf("case %d: want -%s, got %s (%v)", i, test.Want, got, err)
+		}
+	}
+}
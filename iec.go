@@ -0,0 +1,215 @@
+package si
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// BinaryPrefix represents an IEC binary prefix, which denotes a multiplier of a power of 1024.
+// i.e: BinaryPrefixKibi corresponds to "Ki" used to denote a multiplier of 1024 to the unit it is prefixed to.
+// Zebi and yobi are not defined since they exceed the range of an int64.
+type BinaryPrefix uint8
+
+// Package binary prefix definitions.
+const (
+	BinaryPrefixNone BinaryPrefix = iota
+	BinaryPrefixKibi
+	BinaryPrefixMebi
+	BinaryPrefixGibi
+	BinaryPrefixTebi
+	BinaryPrefixPebi
+	BinaryPrefixExbi
+	binaryPrefixInvalid
+)
+
+// IsValid checks if the binary prefix is one of the supported IEC prefixes or the zero base prefix.
+func (p BinaryPrefix) IsValid() bool { return p < binaryPrefixInvalid }
+
+// String returns the two character IEC symbol of the prefix, i.e: "Ki" for [BinaryPrefixKibi].
+// Returns an empty string for [BinaryPrefixNone] and an error message string if the prefix is invalid.
+func (p BinaryPrefix) String() string {
+	const pfxTable = "KiMiGiTiPiEi"
+	switch {
+	case p == BinaryPrefixNone:
+		return ""
+	case !p.IsValid():
+		return "<si!invalid BinaryPrefix>"
+	}
+	return pfxTable[2*p-2 : 2*p]
+}
+
+// Exponent returns the power of 1024 the prefix represents.
+// ie: [BinaryPrefixKibi] returns 1, [BinaryPrefixMebi] returns 2. [BinaryPrefixNone] returns 0.
+func (p BinaryPrefix) Exponent() int { return int(p) }
+
+// shift returns the amount of bits the prefix shifts a value by.
+func (p BinaryPrefix) shift() uint { return 10 * uint(p) }
+
+// ParseBinaryPrefix parses the IEC binary prefix at the start of s. It does not parse BinaryPrefixNone.
+// Returns the prefix, the number of bytes consumed from the input and any error encountered during parsing.
+func ParseBinaryPrefix(s string) (pfx BinaryPrefix, readBytes int, err error) {
+	if len(s) < 2 || s[1] != 'i' {
+		return 0, 0, ErrUnknownPrefix.at(s, 0)
+	}
+	idx := strings.IndexByte("KMGTPE", s[0])
+	if idx < 0 {
+		return 0, 0, ErrUnknownPrefix.at(s, 0)
+	}
+	return BinaryPrefix(idx + 1), 2, nil
+}
+
+// ParseFixedBinary parses a decimal point representation with or without an IEC binary
+// prefix and converts it to a fixed point representation with `baseUnits` as the base units.
+// The number is parsed as in [ParseFixed] and the result is rounded half away from zero.
+// i.e: "512Ki" with baseUnits=BinaryPrefixNone returns 524288 and "1.5Gi" with
+// baseUnits=BinaryPrefixMebi returns 1536.
+//
+// Parsing stops after the prefix, so "1.5GiB" reads 5 bytes.
+// Returns the parsed value in baseUnits, the number of bytes consumed from the input,
+// and any error encountered during parsing.
+func ParseFixedBinary(s string, baseUnits BinaryPrefix) (value int64, readBytes int, err error) {
+	if !baseUnits.IsValid() {
+		return 0, 0, ErrInvalidPrefix
+	}
+	d, readBytes, err := parseDecimal(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	var incomingPrefix BinaryPrefix
+	if readBytes < len(s) {
+		var n int
+		incomingPrefix, n, err = ParseBinaryPrefix(s[readBytes:])
+		if err != nil {
+			return 0, 0, parseErrorAt(err, s, readBytes)
+		}
+		readBytes += n
+	}
+	v, overflow := dtoiBinary(d, int(incomingPrefix)-int(baseUnits))
+	if overflow {
+		return 0, 0, d.overflowErr().at(s, 0)
+	}
+	return v, readBytes, nil
+}
+
+// dtoiBinary converts d to an int64 after scaling it by 1024^scale, rounding half away from zero.
+// Returns true if the result overflows.
+func dtoiBinary(d decimal, scale int) (int64, bool) {
+	var hi, lo uint64
+	var ok bool
+	if scale >= 0 {
+		sh := 10 * uint(scale)
+		if sh > 0 {
+			hi = d.base >> (64 - sh)
+		}
+		hi, lo, ok = scale128(hi, d.base<<sh, d.neg, d.exp, RoundHalfUp)
+	} else if d.exp >= 0 {
+		hi, lo, ok = mul128Pow10(0, d.base, d.exp)
+		hi, lo = shr128Round(hi, lo, 10*uint(-scale))
+	} else {
+		// The decimal fraction is truncated before shifting since it can not
+		// change the rounding of the shift, its integer bits decide it.
+		if -d.exp < len(powerOf10) {
+			lo = d.base / uint64(powerOf10[-d.exp])
+		}
+		hi, lo = shr128Round(0, lo, 10*uint(-scale))
+		ok = true
+	}
+	if !ok || hi != 0 {
+		return 0, true
+	}
+	v, ok := toInt64(lo, d.neg)
+	return v, !ok
+}
+
+// shr128Round shifts the 128-bit magnitude hi:lo right by 0 < sh < 64 bits, rounding half away from zero.
+func shr128Round(hi, lo uint64, sh uint) (uint64, uint64) {
+	roundUp := lo >> (sh - 1) & 1
+	lo = lo>>sh | hi<<(64-sh)
+	hi >>= sh
+	var carry uint64
+	lo, carry = bits.Add64(lo, roundUp, 0)
+	return hi + carry, lo
+}
+
+// AppendFixedBinary formats a fixed-point number with a given magnitude in binary base units and
+// appends it's representation with an IEC binary prefix to the argument buffer.
+// The value is formatted with prec significant digits rounding half away from zero and errors
+// are written to the buffer as in [AppendFixed]. Unlike [AppendFixed] digits of the integer part
+// are never rounded away, so 1000 with prec=1 is "1000" instead of "1k". Values which would round up
+// past the largest int64 are rounded toward zero instead so that [ParseFixedBinary] reads them back.
+//
+//	"512Ki" for value=524288, baseUnits=BinaryPrefixNone, prec=3
+//	"1.5Gi" for value=1536, baseUnits=BinaryPrefixMebi, prec=3
+//	"1023" for value=1023, baseUnits=BinaryPrefixNone, prec=2
+func AppendFixedBinary(b []byte, value int64, baseUnits BinaryPrefix, fmt byte, prec int) []byte {
+	res, err := appendFixedBinary(b, value, baseUnits, fmt, prec)
+	if err != nil {
+		return append(b, formatErrorString(err)...)
+	}
+	return res
+}
+
+func appendFixedBinary(b []byte, value int64, baseUnits BinaryPrefix, fmt byte, prec int) ([]byte, error) {
+	switch {
+	case fmt != 'f':
+		return b, ErrInvalidFormat
+	case prec <= 0:
+		return b, ErrPrecisionTooSmall
+	case !baseUnits.IsValid():
+		return b, ErrInvalidBase
+	case prec >= 21:
+		return b, ErrPrecisionTooLarge
+	case value == 0:
+		return append(b, '0'), nil
+	}
+	u, neg := uabs(value)
+	pfx := baseUnits
+	for pfx+1 < binaryPrefixInvalid && u>>(pfx+1-baseUnits).shift() != 0 {
+		pfx++
+	}
+	// Integer part is followed by the exact fraction digits needed for rounding.
+	var buf [48]byte
+	sh := (pfx - baseUnits).shift()
+	dd := fixedDecimal{
+		digits: strconv.AppendUint(buf[:0], u>>sh, 10),
+		neg:    neg,
+	}
+	dd.lead = len(dd.digits) - 1
+	n := prec
+	if len(dd.digits) > n {
+		n = len(dd.digits)
+	}
+	frac := u & (1<<sh - 1)
+	for len(dd.digits) <= n && frac != 0 {
+		frac *= 10
+		dd.digits = append(dd.digits, byte('0'+frac>>sh))
+		frac &= 1<<sh - 1
+	}
+	var exact [48]byte
+	digits, lead := append(exact[:0], dd.digits...), dd.lead
+	dd.round(n, RoundHalfUp)
+	if !neg && dd.integerPart() >= 1<<(63-sh) {
+		// Rounded up past the largest int64, i.e: MaxInt64 as "8Ei".
+		dd.digits, dd.lead = append(dd.digits[:0], digits...), lead
+		dd.round(n, RoundTowardZero)
+	}
+	if dd.lead == 3 && len(dd.digits) >= 4 && string(dd.digits[:4]) == "1024" && pfx+1 < binaryPrefixInvalid {
+		// Rounded up to the next prefix, i.e: 1023.9Ki -> 1Mi.
+		if neg {
+			b = append(b, '-')
+		}
+		b = append(b, '1')
+		return append(b, (pfx + 1).String()...), nil
+	}
+	b = dd.appendFixed(b, PrefixNone, 0, true)
+	return append(b, pfx.String()...), nil
+}
+
+// integerPart returns the integer part of d. d must not exceed the range of uint64.
+func (d *fixedDecimal) integerPart() (v uint64) {
+	for pos := d.lead; pos >= 0; pos-- {
+		v = v*10 + uint64(d.digitAt(pos)-'0')
+	}
+	return v
+}
@@ -0,0 +1,133 @@
+package si
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAppendFixedBinary(t *testing.T) {
+	var tests = []struct {
+		V     int64
+		BaseU BinaryPrefix
+		Prec  int
+		Want  string
+	}{
+		0: {V: 524288, BaseU: BinaryPrefixNone, Prec: 3, Want: "512Ki"},
+		1: {V: 1536, BaseU: BinaryPrefixMebi, Prec: 3, Want: "1.5Gi"},
+		2: {V: 1023, BaseU: BinaryPrefixNone, Prec: 2, Want: "1023"},
+		3: {V: 1024, BaseU: BinaryPrefixNone, Prec: 3, Want: "1Ki"},
+		4: {V: 1, BaseU: BinaryPrefixExbi, Prec: 3, Want: "1Ei"},
+		5: {V: 1 << 20, BaseU: BinaryPrefixExbi, Prec: 3, Want: "1048576Ei"},
+		6: {V: 3 << 60, BaseU: BinaryPrefixNone, Prec: 4, Want: "3Ei"},
+		// Exact fractions and rounding half away from zero.
+		7:  {V: 1025, BaseU: BinaryPrefixNone, Prec: 11, Want: "1.0009765625Ki"},
+		8:  {V: 1025, BaseU: BinaryPrefixNone, Prec: 4, Want: "1.001Ki"},
+		9:  {V: 1280, BaseU: BinaryPrefixNone, Prec: 2, Want: "1.3Ki"},
+		10: {V: 1152, BaseU: BinaryPrefixNone, Prec: 3, Want: "1.13Ki"},
+		11: {V: 1152, BaseU: BinaryPrefixNone, Prec: 1, Want: "1Ki"},
+		// Rounding carries over to next prefix.
+		12: {V: 1024*1024 - 1, BaseU: BinaryPrefixNone, Prec: 4, Want: "1Mi"},
+		13: {V: 1024*1024 - 1, BaseU: BinaryPrefixNone, Prec: 7, Want: "1023.999Ki"},
+	}
+	var buf [32]byte
+	for i, test := range tests {
+		got := AppendFixedBinary(buf[:0], test.V, test.BaseU, 'f', test.Prec)
+		if string(got) != test.Want {
+			t.Errorf("case %d: want %s, got %s", i, test.Want, got)
+		}
+		got = AppendFixedBinary(buf[:0], -test.V, test.BaseU, 'f', test.Prec)
+		if string(got) != "-"+test.Want {
+			t.Errorf("case %d: want -%s, got %s", i, test.Want, got)
+		}
+	}
+	// Largest values are not rounded up past the int64 range.
+	for i, test := range []struct {
+		V     int64
+		BaseU BinaryPrefix
+		Want  string
+	}{
+		0: {V: math.MaxInt64, BaseU: BinaryPrefixNone, Want: "7.999Ei"},
+		1: {V: math.MinInt64, BaseU: BinaryPrefixNone, Want: "-8Ei"},
+		2: {V: -math.MaxInt64, BaseU: BinaryPrefixNone, Want: "-8Ei"},
+		3: {V: math.MaxInt64, BaseU: BinaryPrefixKibi, Want: "8191Ei"},
+	} {
+		got := AppendFixedBinary(buf[:0], test.V, test.BaseU, 'f', 4)
+		if string(got) != test.Want {
+			t.Errorf("max case %d: want %s, got %s", i, test.Want, got)
+		}
+		if _, _, err := ParseFixedBinary(string(got), test.BaseU); err != nil {
+			t.Errorf("max case %d: parse %s: %v", i, got, err)
+		}
+	}
+	for i, prec := range []int{-1, 0, 21} {
+		if got := AppendFixedBinary(buf[:0], 1, BinaryPrefixNone, 'f', prec); got[0] != '<' {
+			t.Errorf("prec case %d: expected error, got %q", i, got)
+		}
+	}
+	if got := AppendFixedBinary(buf[:0], 1, binaryPrefixInvalid, 'f', 3); got[0] != '<' {
+		t.Errorf("expected error for invalid base, got %q", got)
+	}
+}
+
+func TestParseFixedBinary(t *testing.T) {
+	var tests = []struct {
+		S     string
+		BaseU BinaryPrefix
+		Want  int64
+		N     int // Bytes read, if zero expects all of S read.
+	}{
+		0: {S: "512Ki", BaseU: BinaryPrefixNone, Want: 524288},
+		1: {S: "1.5Gi", BaseU: BinaryPrefixMebi, Want: 1536},
+		2: {S: "1.5GiB", BaseU: BinaryPrefixMebi, Want: 1536, N: 5},
+		3: {S: "2", BaseU: BinaryPrefixNone, Want: 2},
+		4: {S: "8Ei", BaseU: BinaryPrefixKibi, Want: 1 << 53},
+		5: {S: "1e3Ki", BaseU: BinaryPrefixNone, Want: 1_024_000},
+		6: {S: "-0.5Ki", BaseU: BinaryPrefixNone, Want: -512},
+		// Rounding half away from zero to coarser base units.
+		7:  {S: "1536", BaseU: BinaryPrefixKibi, Want: 2},
+		8:  {S: "1535", BaseU: BinaryPrefixKibi, Want: 1},
+		9:  {S: "-1536", BaseU: BinaryPrefixKibi, Want: -2},
+		10: {S: "1535.9", BaseU: BinaryPrefixKibi, Want: 1},
+		11: {S: "1e20", BaseU: BinaryPrefixExbi, Want: 87},
+		12: {S: "1e-5Mi", BaseU: BinaryPrefixNone, Want: 10},
+	}
+	for i, test := range tests {
+		v, n, err := ParseFixedBinary(test.S, test.BaseU)
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		wantN := test.N
+		if wantN == 0 {
+			wantN = len(test.S)
+		}
+		if n != wantN {
+			t.Errorf("case %d: bytes read mismatch, got %d want %d", i, n, wantN)
+		}
+		if v != test.Want {
+			t.Errorf("case %d: got %d, want %d from %q", i, v, test.Want, test.S)
+		}
+	}
+	for i, s := range []string{"8Ei", "1Zi", "", "Ki", "1e30"} {
+		_, _, err := ParseFixedBinary(s, BinaryPrefixNone)
+		if err == nil {
+			t.Errorf("error case %d: expected error for %q", i, s)
+		}
+	}
+}
+
+func TestFormatParseBinaryLoop(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var buf [32]byte
+	for i := 0; i < 1000; i++ {
+		// Values under 1e18 formatted with 19 digits are within half a base unit.
+		v := rng.Int63n(1e18) >> rng.Intn(60)
+		base := BinaryPrefix(rng.Intn(int(binaryPrefixInvalid)))
+		s := AppendFixedBinary(buf[:0], v, base, 'f', 19)
+		got, n, err := ParseFixedBinary(string(s), base)
+		if err != nil || n != len(s) || got != v {
+			t.Fatalf("%d %s: got %d (%v), formatted %q", v, base, got, err, s)
+		}
+	}
+}
@@ -0,0 +1,464 @@
+package si
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"unicode/utf8"
+)
+
+// Quantity is a physical quantity stored as a fixed-point value in units of a
+// base [Prefix] together with its [Dimension].
+// i.e: value=3300, base=[PrefixMilli] and the dimension of electric current represents 3.3A.
+//
+// The zero value of a Quantity is a dimensionless zero.
+type Quantity struct {
+	value int64
+	base  Prefix
+	dim   Dimension
+}
+
+// NewQuantity creates a new quantity from a fixed-point value expressed in
+// baseUnits and its dimension. It returns an error if baseUnits is not a valid prefix.
+func NewQuantity(value int64, baseUnits Prefix, dim Dimension) (Quantity, error) {
+	if !baseUnits.IsValid() {
+		return Quantity{}, ErrInvalidPrefix
+	}
+	return Quantity{value: value, base: baseUnits, dim: dim}, nil
+}
+
+// Fixed returns the fixed-point value of q. It is expressed in units of q.Base().
+func (q Quantity) Fixed() int64 { return q.value }
+
+// Base returns the prefix of the units q's fixed-point value is expressed in.
+func (q Quantity) Base() Prefix { return q.base }
+
+// Dimension returns the dimension of q.
+func (q Quantity) Dimension() Dimension { return q.dim }
+
+// Float returns q's magnitude in SI base units as a floating point number. See [FixedToFloat].
+func (q Quantity) Float() float64 { return FixedToFloat(q.value, q.base) }
+
+// String returns a human readable representation of q with SI units and
+// as many digits as needed to represent it exactly, i.e: "3.3mA" or "4.7kΩ".
+// Units are simplified with the SI derived units, see [Quantity.AppendFormat].
+func (q Quantity) String() string {
+	return string(q.AppendFormat(make([]byte, 0, 24), nil, 'f', -1))
+}
+
+// AppendFormat appends the representation of q to b. The magnitude is formatted
+// by [AppendFixed] with the fmt and prec arguments and is followed by the unit as formatted by df. Magnitudes beyond the range of the SI prefixes
+// are formatted with the 'e' fmt instead of 'f', as are dimensionless quantities whose prefix would be
+// read back as a unit by [ParseQuantity]: a dimensionless 5 milli is "5e-03" since "5m" is five metres.
+// A prefix is only attached to a first unit with an exponent of one other than the kilogram, so
+// a mass of 5000kg is formatted as "5e+03kg" and an area of 10⁶m² as "1e+06m²" instead of "1Mm²".
+// If df is nil the SI unit symbols are used and simplified with the SI derived units. A negative prec formats q with as many
+// digits as needed to represent it exactly, omitting trailing zeros of the decimal part.
+//
+// Derived units are chosen by dimension alone so quantities of a different kind which share a dimension can not
+// be told apart: a torque of 3.5kN·m is formatted as the energy "3.5kJ" and any s⁻¹ as "Hz".
+// Use a df created from [DefaultDimensionFormatterConfig] to format with the SI base units only.
+func (q Quantity) AppendFormat(b []byte, df *DimensionFormatter, fmt byte, prec int) []byte {
+	if df == nil {
+		df = siDimFormatter
+	}
+	res, err := q.appendMagnitude(b, df, fmt, prec)
+	if err != nil {
+		res = append(b, formatErrorString(err)...)
+	}
+	return df.AppendFormat(res, q.dim)
+}
+
+// appendMagnitude appends the magnitude of q formatted as in [Quantity.AppendFormat] with df to b.
+// On error b is returned unmodified together with one of the formatting errors.
+func (q Quantity) appendMagnitude(b []byte, df *DimensionFormatter, fmt byte, prec int) ([]byte, error) {
+	strip := prec < 0
+	if strip {
+		prec = fixedDigits(q.value)
+	}
+	f := FixedFormat{Fmt: fmt, Prec: prec, stripZeros: strip}
+	res, err := f.AppendErr(b, q.value, q.base)
+	prefixed := err == nil && endsWithPrefix(res[len(b):])
+	if err == ErrUnrepresentable || prefixed && q.dim.IsDimensionless() && endsWithUnit(res[len(b):]) ||
+		prefixed && !df.prefixable(q.dim) {
+		f.Fmt = 'e'
+		res, err = f.AppendErr(b, q.value, q.base)
+	}
+	return res, err
+}
+
+// endsWithPrefix reports whether the formatted magnitude num ends with a prefix symbol.
+func endsWithPrefix(num []byte) bool {
+	last := num[len(num)-1]
+	return last < '0' || last > '9'
+}
+
+// endsWithUnit reports whether the prefix symbol that ends the formatted magnitude num is also
+// an SI unit symbol, such as "m" and "T", which [ParseQuantity] reads as a unit.
+func endsWithUnit(num []byte) bool {
+	i := len(num)
+	for i > 0 && (num[i-1] < '0' || num[i-1] > '9') {
+		i--
+	}
+	sym := string(num[i:])
+	_, n, err := siDimFormatter.ParseDimension(sym)
+	return err == nil && n > 0 && n == len(sym)
+}
+
+// Format implements [fmt.Formatter]. The 'f', 'e' and 'g' verbs format the magnitude as in [Quantity.AppendFormat]
+// and 'v' and 's' are equivalent to 'f'. The precision is the amount of significant digits and if
+// omitted q is formatted with as many digits as needed to represent it exactly. A precision which
+// [AppendFixed] does not accept is reported as "%!v(BADPREC)". The '+' and ' ' flags
+// print a plus sign or space before non-negative quantities. Output shorter than the width is padded with
+// spaces on the left, or on the right if the '-' flag is set. The '0' flag pads with zeros after the sign.
+//
+//	fmt.Sprintf("%8.3v|%-8v|%+.2e", q, q, q) // "  3.30mA|3.3mA   |+3.3e-03A" for 3.3 milliamperes.
+func (q Quantity) Format(s fmt.State, verb rune) {
+	var fmtVerb byte
+	switch verb {
+	case 'v', 's', 'f':
+		fmtVerb = 'f'
+	case 'e', 'g':
+		fmtVerb = byte(verb)
+	default:
+		fmt.Fprintf(s, "%%!%c(si.Quantity=%s)", verb, q.String())
+		return
+	}
+	prec, ok := s.Precision()
+	if !ok {
+		prec = -1
+	}
+	var buf [64]byte
+	b := buf[:0]
+	if q.value >= 0 && s.Flag('+') {
+		b = append(b, '+')
+	} else if q.value >= 0 && s.Flag(' ') {
+		b = append(b, ' ')
+	}
+	b, err := q.appendMagnitude(b, siDimFormatter, fmtVerb, prec)
+	if err != nil {
+		fmt.Fprintf(s, "%%!%c(BADPREC)", verb)
+		return
+	}
+	b = siDimFormatter.AppendFormat(b, q.dim)
+	width, ok := s.Width()
+	pad := width - utf8.RuneCount(b)
+	switch {
+	case !ok || pad <= 0:
+		s.Write(b)
+	case s.Flag('-'):
+		s.Write(b)
+		writePadding(s, ' ', pad)
+	case s.Flag('0'):
+		var sign int
+		if b[0] == '+' || b[0] == '-' || b[0] == ' ' {
+			sign = 1
+		}
+		s.Write(b[:sign])
+		writePadding(s, '0', pad)
+		s.Write(b[sign:])
+	default:
+		writePadding(s, ' ', pad)
+		s.Write(b)
+	}
+}
+
+// writePadding writes n copies of c to w.
+func writePadding(w io.Writer, c byte, n int) {
+	var buf [16]byte
+	for i := range buf {
+		buf[i] = c
+	}
+	for n > 0 {
+		chunk := n
+		if chunk > len(buf) {
+			chunk = len(buf)
+		}
+		w.Write(buf[:chunk])
+		n -= chunk
+	}
+}
+
+// ParseQuantity parses a number with an optional SI prefix and unit and converts it to
+// a quantity with `baseUnits` as the base units of its fixed-point value. The number is
+// parsed as in [ParseFixed] and may be separated from the unit by a single space.
+// Units are written in the notation emitted by [Quantity.String] and may include the
+// units listed by [SIDerivedUnits] other than degree Celsius, becquerel, gray, sievert and katal. The ASCII alternatives '*' as separator, '^' for
+// exponents and '/' for division of the following term are also accepted:
+//   - "9.81m·s⁻²", "9.81 m*s^-2" and "9.81 m/s^2" all represent an acceleration.
+//   - "3.5kN·m" and "3.5km·N" both represent a torque of 3500N·m.
+//   - "2.5k" is a dimensionless 2500.
+//
+// As in SI notation the prefix is part of the unit it precedes and is raised to its exponent,
+// so "1km²" is 10⁶m², "1cm³" is 10⁻⁶m³ and "1ks⁻¹" is 1mHz.
+//
+// A character which may be read as either a prefix or a unit is read as a unit unless
+// reading it as a prefix consumes more of the input: "5m" is five metres while "5ms" is five milliseconds.
+//
+// Parsing stops at the first character that is not part of the quantity. Returns the parsed
+// quantity, the number of bytes consumed from the input and any error encountered during parsing.
+func ParseQuantity(s string, baseUnits Prefix) (q Quantity, readBytes int, err error) {
+	if !baseUnits.IsValid() {
+		return Quantity{}, 0, ErrInvalidPrefix
+	}
+	d, readBytes, err := parseDecimal(s)
+	if err != nil {
+		return Quantity{}, 0, err
+	}
+	scale, dim, n, err := parseUnit(s[readBytes:])
+	if err != nil {
+		return Quantity{}, 0, parseErrorAt(err, s, readBytes)
+	}
+	v, overflow := dtoi(d, scale-baseUnits.Exponent(), RoundHalfUp)
+	if overflow {
+		return Quantity{}, 0, d.overflowErr().at(s, 0)
+	}
+	return Quantity{value: v, base: baseUnits, dim: dim}, readBytes + n, nil
+}
+
+// parseUnit parses the optionally prefixed unit that follows a number and returns the power
+// of ten of the prefix raised to the exponent of the first unit. It reads no bytes if there
+// is no unit. A prefix with no unit is only read if not preceded by a space.
+func parseUnit(s string) (scale int, dim Dimension, readBytes int, err error) {
+	input := s
+	spaced := len(s) > 0 && s[0] == ' '
+	if spaced {
+		s = s[1:]
+	}
+	dim, readBytes, err = siDimFormatter.ParseDimension(s)
+	if err != nil {
+		return 0, Dimension{}, 0, parseErrorAt(err, input, b2i(spaced))
+	}
+	if p, size, perr := ParsePrefix(s); perr == nil {
+		pdim, n, err := siDimFormatter.ParseDimension(s[size:])
+		if err != nil {
+			return 0, Dimension{}, 0, parseErrorAt(err, input, b2i(spaced)+size)
+		}
+		if size+n > readBytes && (n > 0 || !spaced) {
+			exp := 1
+			if _, un := siDimFormatter.matchUnit(s[size:]); un > 0 {
+				exp, _, _ = parseUnitExponent(s[size+un:]) // Validated by ParseDimension.
+			}
+			scale, dim, readBytes = p.Exponent()*exp, pdim, size+n
+		}
+	}
+	if readBytes == 0 {
+		return 0, Dimension{}, 0, nil
+	}
+	return scale, dim, readBytes + b2i(spaced), nil
+}
+
+// fixedDigits returns the amount of base 10 digits in v, with a minimum of 1.
+func fixedDigits(v int64) int {
+	if v < 0 {
+		v = -v
+	}
+	switch {
+	case v == 0:
+		return 1
+	case v < 0:
+		return len(powerOf10) // MinInt64 negation overflows.
+	}
+	return ilog10(v) + 1
+}
+
+// Quantity arithmetic errors. Results which do not fit in an int64 return [ErrOverflow].
+var (
+	ErrDimMismatch = errors.New("dimension mismatch")
+	ErrDivByZero   = errors.New("division by zero")
+)
+
+// AddQuantity returns the quantity obtained from a+b. The result is expressed in
+// the finer of the base prefixes of a and b so that no precision is lost.
+// It returns [ErrDimMismatch] if the dimensions of a and b differ and [ErrOverflow] if the result overflows.
+func AddQuantity(a, b Quantity) (Quantity, error) {
+	av, bv, base, err := alignQuantities(a, b)
+	if err != nil {
+		return Quantity{}, err
+	}
+	sum := av + bv
+	if (av > 0 && bv > 0 && sum < 0) || (av < 0 && bv < 0 && sum >= 0) {
+		return Quantity{}, ErrOverflow
+	}
+	return Quantity{value: sum, base: base, dim: a.dim}, nil
+}
+
+// SubQuantity returns the quantity obtained from a-b. See [AddQuantity].
+func SubQuantity(a, b Quantity) (Quantity, error) {
+	av, bv, base, err := alignQuantities(a, b)
+	if err != nil {
+		return Quantity{}, err
+	}
+	diff := av - bv
+	if (av >= 0 && bv < 0 && diff < 0) || (av < 0 && bv > 0 && diff >= 0) {
+		return Quantity{}, ErrOverflow
+	}
+	return Quantity{value: diff, base: base, dim: a.dim}, nil
+}
+
+// alignQuantities checks that a and b share a dimension and returns their values
+// expressed in the finer of their base prefixes.
+func alignQuantities(a, b Quantity) (av, bv int64, base Prefix, err error) {
+	if a.dim != b.dim {
+		return 0, 0, 0, ErrDimMismatch
+	}
+	base = a.base
+	if b.base < base {
+		base = b.base
+	}
+	av, overflow := rescale(a.value, a.base, base, RoundHalfUp)
+	if overflow {
+		return 0, 0, 0, ErrOverflow
+	}
+	bv, overflow = rescale(b.value, b.base, base, RoundHalfUp)
+	if overflow {
+		return 0, 0, 0, ErrOverflow
+	}
+	return av, bv, base, nil
+}
+
+// MulQuantity returns the quantity obtained from a*b. The dimensions are combined
+// as in [MulDim] and the result is expressed in the product of the base prefixes of a and b,
+// i.e: milliamps times millivolts gives microwatts. Products that are not a valid prefix, such as
+// centi times milli, are expressed in the engineering prefix below them. If the result does not fit in that prefix
+// it is rescaled to the next larger prefix that fits, rounding half away from zero.
+// It returns an error if the result dimension or value can not be represented.
+func MulQuantity(a, b Quantity) (Quantity, error) {
+	dim, err := MulDim(a.dim, b.dim)
+	if err != nil {
+		return Quantity{}, err
+	}
+	ua, nega := uabs(a.value)
+	ub, negb := uabs(b.value)
+	hi, lo := bits.Mul64(ua, ub)
+	v, base, ok := fit128(hi, lo, nega != negb, int(a.base)+int(b.base))
+	if !ok {
+		return Quantity{}, ErrOverflow
+	}
+	return Quantity{value: v, base: base, dim: dim}, nil
+}
+
+// DivQuantity returns the quantity obtained from a/b. The dimensions are combined
+// as in [DivDim] and the result is expressed in the finest of the base prefixes of a and b
+// and their quotient, rounding half away from zero. A quotient that is not a valid prefix is
+// replaced by the engineering prefix below it. i.e: 3300mW divided by 1500mA gives 2200mV.
+// Inexact quotients with fewer significant digits than the operands are expressed in the next finer
+// engineering prefixes until they have as many digits or no longer fit, so 1A divided by 3A is a dimensionless 333 milli.
+// It returns [ErrDivByZero] if b is zero and an error if the result dimension or value can not be represented.
+func DivQuantity(a, b Quantity) (Quantity, error) {
+	if b.value == 0 {
+		return Quantity{}, ErrDivByZero
+	}
+	dim, err := DivDim(a.dim, b.dim)
+	if err != nil {
+		return Quantity{}, err
+	}
+	base := int(a.base) - int(b.base)
+	if a.base < Prefix(base) {
+		base = int(a.base)
+	}
+	if b.base < Prefix(base) {
+		base = int(b.base)
+	}
+	if base < int(PrefixQuecto) {
+		base = int(PrefixQuecto)
+	} else if !Prefix(base).IsValid() {
+		base -= mod3(base) // Round down to engineering prefix.
+	}
+	digits := fixedDigits(a.value)
+	if n := fixedDigits(b.value); n > digits {
+		digits = n
+	}
+	ua, nega := uabs(a.value)
+	ub, negb := uabs(b.value)
+	res := Quantity{dim: dim}
+	for found := false; ; found = true {
+		v, trunc, exact, ok := divPow10(ua, ub, int(a.base)-int(b.base)-base, nega != negb)
+		if !ok && found {
+			return res, nil // Finer prefix overflows, keep the coarser quotient.
+		} else if !ok {
+			return Quantity{}, ErrOverflow
+		}
+		res.value, res.base = v, Prefix(base)
+		if exact || trunc >= uint64(powerOf10[digits-1]) || base == int(PrefixQuecto) {
+			return res, nil
+		}
+		base -= 1 + mod3(base-1) // Next finer engineering prefix.
+	}
+}
+
+// divPow10 returns the quotient of ua·10^scale divided by ub rounded half away from zero
+// and negated if neg is set, along with the truncated magnitude of the quotient and whether
+// the division is exact. It returns false if the quotient does not fit in an int64.
+func divPow10(ua, ub uint64, scale int, neg bool) (v int64, trunc uint64, exact, ok bool) {
+	// Scale numerator or denominator so that quotient is in base units.
+	var nhi, nlo, dhi, dlo uint64
+	if scale >= 0 {
+		nhi, nlo, ok = mul128Pow10(0, ua, scale)
+		dlo = ub
+	} else {
+		nlo = ua
+		dhi, dlo, ok = mul128Pow10(0, ub, -scale)
+		if !ok {
+			dhi, ok = math.MaxUint64, true // Denominator exceeds 128 bits so quotient rounds to zero.
+		}
+	}
+	if !ok || dhi == 0 && nhi >= dlo {
+		return 0, 0, false, false
+	}
+	if dhi != 0 {
+		// Denominator exceeds numerator by more than twice, quotient rounds to zero.
+		return 0, 0, ua == 0, true
+	}
+	q, r := bits.Div64(nhi, nlo, dlo)
+	trunc = q
+	if r >= dlo-r {
+		q++ // Round half away from zero.
+		if q == 0 {
+			return 0, 0, false, false
+		}
+	}
+	v, ok = toInt64(q, neg)
+	return v, trunc, r == 0, ok
+}
+
+// rescale converts v expressed in from units to to units rounding with mode.
+// Returns true if the result overflows.
+func rescale(v int64, from, to Prefix, mode RoundingMode) (int64, bool) {
+	u, neg := uabs(v)
+	hi, lo, ok := scale128(0, u, neg, int(from)-int(to), mode)
+	if !ok || hi != 0 {
+		return 0, true
+	}
+	v, ok = toInt64(lo, neg)
+	return v, !ok
+}
+
+// fit128 finds the smallest valid prefix in which the 128-bit magnitude hi:lo
+// expressed in units of 10^exp fits in an int64. The prefixes tried are exp itself,
+// or the engineering prefix below it if exp is not a valid prefix, followed by the
+// engineering prefixes above it.
+func fit128(hi, lo uint64, neg bool, exp int) (int64, Prefix, bool) {
+	base := exp
+	if base < int(PrefixQuecto) {
+		base = int(PrefixQuecto)
+	} else if base > int(PrefixQuetta) {
+		base = int(PrefixQuetta)
+	}
+	if !Prefix(base).IsValid() {
+		base -= mod3(base) // Round down to engineering prefix so no precision is lost.
+	}
+	for ; base <= int(PrefixQuetta); base += 3 - mod3(base) {
+		qhi, qlo, ok := scale128(hi, lo, neg, exp-base, RoundHalfUp)
+		if !ok || qhi != 0 {
+			continue
+		}
+		v, ok := toInt64(qlo, neg)
+		if ok {
+			return v, Prefix(base), true
+		}
+	}
+	return 0, 0, false
+}
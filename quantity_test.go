@@ -0,0 +1,405 @@
+package si
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuantityString(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	accel := newdim([]int{1, 0, -2, 0, 0, 0, 0})
+	var tests = []struct {
+		V     int64
+		BaseU Prefix
+		Dim   Dimension
+		Want  string
+	}{
+		0: {V: 0, BaseU: PrefixNone, Want: "0"},
+		1: {V: 3300, BaseU: PrefixMilli, Dim: current, Want: "3.3A"},
+		2: {V: -2500, BaseU: PrefixMicro, Dim: current, Want: "-2.5mA"},
+		3: {V: 981, BaseU: PrefixMilli, Dim: accel, Want: "981mm·s⁻²"},
+		4: {V: 1_234_567, BaseU: PrefixNone, Want: "1.234567M"},
+		5: {V: 1000, BaseU: PrefixKilo, Dim: current, Want: "1MA"},
+	}
+	for i, test := range tests {
+		q, err := NewQuantity(test.V, test.BaseU, test.Dim)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Fixed() != test.V || q.Base() != test.BaseU || q.Dimension() != test.Dim {
+			t.Errorf("case %d: accessor mismatch", i)
+		}
+		got := q.String()
+		if got != test.Want {
+			t.Errorf("case %d: want %q, got %q", i, test.Want, got)
+		}
+	}
+}
+
+func TestQuantityAppendFormat(t *testing.T) {
+	dim := newdim([]int{1, 2, 3, 4, 5, 6, 7})
+	q, err := NewQuantity(1_234_567, PrefixMilli, dim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(q.AppendFormat(nil, nil, 'f', 3))
+	if got != "1.23km·kg²·s³·K⁴·A⁵·cd⁶·mol⁷" {
+		t.Error("bad SI format", got)
+	}
+	got = string(q.AppendFormat(nil, abstractDimFormatter, 'f', 2))
+	if got != "1.2kLM²T³K⁴I⁵J⁶N⁷" {
+		t.Error("bad abstract format", got)
+	}
+	// Derived units can not tell torque from energy.
+	torque, _ := NewQuantity(3500, PrefixNone, newdim([]int{2, 1, -2, 0, 0, 0, 0}))
+	baseDF, _ := NewDimensionFormatter(DefaultDimensionFormatterConfig())
+	if got = torque.String(); got != "3.5kJ" {
+		t.Error("bad derived format", got)
+	}
+	if got = string(torque.AppendFormat(nil, baseDF, 'f', -1)); got != "3.5e+03m²·kg·s⁻²" {
+		t.Error("bad base unit format", got)
+	}
+	// Prefixes are only attached to a first unit with exponent one other than the kilogram.
+	for i, test := range []struct {
+		V    int64
+		Dim  Dimension
+		Want string
+	}{
+		0: {V: 5000, Dim: newdim([]int{0, 1, 0, 0, 0, 0, 0}), Want: "5e+03kg"},
+		1: {V: 1_000_000, Dim: newdim([]int{2, 0, 0, 0, 0, 0, 0}), Want: "1e+06m²"},
+		2: {V: 1000, Dim: newdim([]int{-3, 1, 0, 0, 0, 0, 0}), Want: "1e+03m⁻³·kg"},
+		3: {V: 5, Dim: newdim([]int{0, 1, 0, 0, 0, 0, 0}), Want: "5kg"},
+		4: {V: 5000, Dim: newdim([]int{1, 1, 0, 0, 0, 0, 0}), Want: "5km·kg"},
+	} {
+		q, _ = NewQuantity(test.V, PrefixNone, test.Dim)
+		if got = string(q.AppendFormat(nil, baseDF, 'f', -1)); got != test.Want {
+			t.Errorf("case %d: want %s, got %s", i, test.Want, got)
+		}
+	}
+	// Trailing zeros are only omitted if no precision is given.
+	q, _ = NewQuantity(3300, PrefixMilli, Dimension{})
+	if got = string(q.AppendFormat(nil, nil, 'f', -1)); got != "3.3" {
+		t.Error("trailing zeros not omitted", got)
+	}
+	if got = string(q.AppendFormat(nil, nil, 'f', 4)); got != "3.300" {
+		t.Error("trailing zeros omitted with precision", got)
+	}
+	_, err = NewQuantity(1, PrefixMilli-1, dim)
+	if err == nil {
+		t.Error("expected error for invalid base prefix")
+	}
+}
+
+func TestQuantityFormatter(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	ma, _ := NewQuantity(3300, PrefixMicro, current)
+	neg, _ := NewQuantity(-1_234_567, PrefixNone, Dimension{})
+	huge, _ := NewQuantity(9_000_000_000_000_000_000, PrefixExa, current)
+	var tests = []struct {
+		Format string
+		Q      Quantity
+		Want   string
+	}{
+		0:  {Format: "%v", Q: ma, Want: "3.3mA"},
+		1:  {Format: "%s", Q: ma, Want: "3.3mA"},
+		2:  {Format: "%8.3v", Q: ma, Want: "  3.30mA"},
+		3:  {Format: "%-8v|", Q: ma, Want: "3.3mA   |"},
+		4:  {Format: "%+v", Q: ma, Want: "+3.3mA"},
+		5:  {Format: "% v", Q: ma, Want: " 3.3mA"},
+		6:  {Format: "%08v", Q: ma, Want: "0003.3mA"},
+		7:  {Format: "%+08v", Q: ma, Want: "+003.3mA"},
+		8:  {Format: "%.2f", Q: neg, Want: "-1.2M"},
+		9:  {Format: "%09.2v", Q: neg, Want: "-00001.2M"},
+		10: {Format: "%+v", Q: neg, Want: "-1.234567M"},
+		11: {Format: "%.3e", Q: neg, Want: "-1.23e+06"},
+		12: {Format: "%g", Q: ma, Want: "3.3mA"},
+		13: {Format: "%3v", Q: ma, Want: "3.3mA"},
+		14: {Format: "%d", Q: ma, Want: "%!d(si.Quantity=3.3mA)"},
+		// Width counts runes.
+		15: {Format: "%7v", Q: Quantity{value: 5, base: PrefixMicro}, Want: "     5μ"},
+		// Precision out of range.
+		16: {Format: "%.0v", Q: ma, Want: "%!v(BADPREC)"},
+		17: {Format: "%+.25v", Q: ma, Want: "%!v(BADPREC)"},
+		18: {Format: "%.0e", Q: ma, Want: "%!e(BADPREC)"},
+		// Magnitudes beyond the quetta prefix use scientific notation.
+		19: {Format: "%v", Q: huge, Want: "9e+36A"},
+		20: {Format: "%.3f", Q: huge, Want: "9e+36A"},
+	}
+	for i, test := range tests {
+		got := fmt.Sprintf(test.Format, test.Q)
+		if got != test.Want {
+			t.Errorf("case %d: want %q, got %q", i, test.Want, got)
+		}
+	}
+}
+
+func TestQuantityArithmetic(t *testing.T) {
+	var (
+		current = newdim([]int{0, 0, 0, 0, 1, 0, 0})
+		voltage = newdim([]int{2, 1, -3, 0, -1, 0, 0})
+		power   = newdim([]int{2, 1, -3, 0, 0, 0, 0})
+		length  = newdim([]int{1, 0, 0, 0, 0, 0, 0})
+		ohm     = newdim([]int{2, 1, -3, 0, -2, 0, 0})
+		area    = newdim([]int{2, 0, 0, 0, 0, 0, 0})
+		none    Dimension
+	)
+	q := func(v int64, base Prefix, dim Dimension) Quantity {
+		qt, err := NewQuantity(v, base, dim)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return qt
+	}
+	var tests = []struct {
+		Op   func(a, b Quantity) (Quantity, error)
+		A, B Quantity
+		Want Quantity
+		Err  error
+	}{
+		// Addition and subtraction rescale to finer prefix.
+		0: {Op: AddQuantity, A: q(1, PrefixNone, current), B: q(500, PrefixMilli, current), Want: q(1500, PrefixMilli, current)},
+		1: {Op: SubQuantity, A: q(1, PrefixNone, current), B: q(1500, PrefixMilli, current), Want: q(-500, PrefixMilli, current)},
+		2: {Op: AddQuantity, A: q(2, PrefixKilo, length), B: q(3, PrefixKilo, length), Want: q(5, PrefixKilo, length)},
+		3: {Op: AddQuantity, A: q(1, PrefixNone, current), B: q(1, PrefixNone, voltage), Err: ErrDimMismatch},
+		4: {Op: AddQuantity, A: q(math.MaxInt64, PrefixNone, none), B: q(1, PrefixNone, none), Err: ErrOverflow},
+		5: {Op: AddQuantity, A: q(10, PrefixExa, none), B: q(1, PrefixAtto, none), Err: ErrOverflow},
+		6: {Op: SubQuantity, A: q(-1, PrefixNone, none), B: q(math.MinInt64, PrefixNone, none), Want: q(math.MaxInt64, PrefixNone, none)},
+		// Multiplication combines prefixes.
+		7:  {Op: MulQuantity, A: q(3, PrefixMilli, current), B: q(5, PrefixNone, voltage), Want: q(15, PrefixMilli, power)},
+		8:  {Op: MulQuantity, A: q(3, PrefixMilli, current), B: q(5, PrefixMilli, voltage), Want: q(15, PrefixMicro, power)},
+		9:  {Op: MulQuantity, A: q(-2, PrefixKilo, current), B: q(4, PrefixKilo, voltage), Want: q(-8, PrefixMega, power)},
+		10: {Op: MulQuantity, A: q(1_000_000_000_000, PrefixMilli, none), B: q(1_000_000_000_000, PrefixMilli, none), Want: q(1_000_000_000_000_000_000, PrefixNone, none)},
+		11: {Op: MulQuantity, A: q(1_500, PrefixAtto, none), B: q(2, PrefixAtto, none), Want: q(0, PrefixQuecto, none)},
+		12: {Op: MulQuantity, A: q(1_500_000_000_000_000_000, PrefixAtto, none), B: q(1, PrefixAtto, none), Want: q(1_500_000_000_000, PrefixQuecto, none)},
+		13: {Op: MulQuantity, A: q(math.MaxInt64, PrefixExa, none), B: q(math.MaxInt64, PrefixNone, none), Err: ErrOverflow},
+		14: {Op: MulQuantity, A: q(1, PrefixExa, none), B: q(2, PrefixKilo, none), Want: q(2, PrefixZetta, none)},
+		// Division keeps finest base.
+		15: {Op: DivQuantity, A: q(3300, PrefixMilli, power), B: q(1500, PrefixMilli, current), Want: q(2200, PrefixMilli, voltage)},
+		16: {Op: DivQuantity, A: q(1, PrefixNone, current), B: q(3, PrefixNone, current), Want: q(333, PrefixMilli, none)},
+		17: {Op: DivQuantity, A: q(2, PrefixNone, none), B: q(3, PrefixNone, none), Want: q(667, PrefixMilli, none)},
+		18: {Op: DivQuantity, A: q(-3, PrefixMilli, voltage), B: q(1, PrefixKilo, current), Want: q(-3, PrefixMicro, ohm)},
+		19: {Op: DivQuantity, A: q(1, PrefixNone, none), B: q(0, PrefixNone, none), Err: ErrDivByZero},
+		20: {Op: DivQuantity, A: q(math.MaxInt64, PrefixExa, none), B: q(1, PrefixAtto, none), Err: ErrOverflow},
+		21: {Op: DivQuantity, A: q(5, PrefixAtto, none), B: q(1, PrefixExa, none), Want: q(0, PrefixQuecto, none)},
+		// Denominator scaled past 64 bits with a zero low word.
+		28: {Op: DivQuantity, A: q(1, PrefixQuecto, none), B: q(1<<61, PrefixKilo, none), Want: q(0, PrefixQuecto, none)},
+		29: {Op: DivQuantity, A: q(1, PrefixQuecto, none), B: q(1<<34, PrefixQuetta, none), Want: q(0, PrefixQuecto, none)},
+		// Non-engineering prefixes.
+		22: {Op: MulQuantity, A: q(3, PrefixCenti, length), B: q(5, PrefixMilli, length), Want: q(150, PrefixMicro, area)},
+		23: {Op: MulQuantity, A: q(3, PrefixCenti, length), B: q(5, PrefixCenti, length), Want: q(1500, PrefixMicro, area)},
+		24: {Op: MulQuantity, A: q(3, PrefixHecto, length), B: q(5, PrefixHecto, length), Want: q(150, PrefixKilo, area)},
+		25: {Op: AddQuantity, A: q(1, PrefixDeci, length), B: q(25, PrefixCenti, length), Want: q(35, PrefixCenti, length)},
+		26: {Op: DivQuantity, A: q(3, PrefixMilli, area), B: q(2, PrefixCenti, length), Want: q(150, PrefixMilli, length)},
+		27: {Op: DivQuantity, A: q(3, PrefixHecto, area), B: q(2, PrefixMilli, length), Want: q(150_000_000, PrefixMilli, length)},
+		// Inexact quotients move to finer prefixes to keep the operands' significant digits.
+		33: {Op: DivQuantity, A: q(10, PrefixNone, none), B: q(3, PrefixNone, none), Want: q(3333, PrefixMilli, none)},
+		34: {Op: DivQuantity, A: q(1, PrefixKilo, none), B: q(7, PrefixNone, none), Want: q(143, PrefixNone, none)},
+		35: {Op: DivQuantity, A: q(1_000_000_000_000_000_000, PrefixNone, none), B: q(9_000_000_000_000_000_000, PrefixNone, none), Want: q(111_111_111_111_111_111, PrefixAtto, none)},
+		// Subtraction overflow and dimension checks.
+		30: {Op: SubQuantity, A: q(0, PrefixNone, none), B: q(math.MinInt64, PrefixNone, none), Err: ErrOverflow},
+		31: {Op: SubQuantity, A: q(math.MinInt64, PrefixNone, none), B: q(1, PrefixNone, none), Err: ErrOverflow},
+		32: {Op: SubQuantity, A: q(1, PrefixNone, current), B: q(math.MinInt64, PrefixNone, voltage), Err: ErrDimMismatch},
+	}
+	for i, test := range tests {
+		got, err := test.Op(test.A, test.B)
+		if test.Err != nil {
+			if !errors.Is(err, test.Err) {
+				t.Errorf("case %d: want error %v, got %v (%v)", i, test.Err, err, got)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		if got != test.Want {
+			t.Errorf("case %d: want %d%s %s, got %d%s %s", i, test.Want.Fixed(), test.Want.Base(), test.Want.Dimension(), got.Fixed(), got.Base(), got.Dimension())
+		}
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	var (
+		accel  = newdim([]int{1, 0, -2, 0, 0, 0, 0})
+		torque = newdim([]int{2, 1, -2, 0, 0, 0, 0})
+		length = newdim([]int{1, 0, 0, 0, 0, 0, 0})
+		timed  = newdim([]int{0, 0, 1, 0, 0, 0, 0})
+		mass   = newdim([]int{0, 1, 0, 0, 0, 0, 0})
+		amount = newdim([]int{0, 0, 0, 0, 0, 0, 1})
+		area   = newdim([]int{2, 0, 0, 0, 0, 0, 0})
+		volume = newdim([]int{3, 0, 0, 0, 0, 0, 0})
+		freq   = newdim([]int{0, 0, -1, 0, 0, 0, 0})
+	)
+	var tests = []struct {
+		S     string
+		BaseU Prefix
+		Want  int64
+		Dim   Dimension
+		N     int // Bytes read, if zero expects all of S read.
+	}{
+		// Superscript notation as printed by Quantity.String.
+		0: {S: "9.81m·s⁻²", BaseU: PrefixMilli, Want: 9810, Dim: accel},
+		1: {S: "9.81 m·s⁻²", BaseU: PrefixMilli, Want: 9810, Dim: accel},
+		2: {S: "3.5km·kg·m·s⁻²", BaseU: PrefixNone, Want: 3500, Dim: torque},
+		// ASCII alternatives.
+		3: {S: "9.81 m*s^-2", BaseU: PrefixMilli, Want: 9810, Dim: accel},
+		4: {S: "9.81 m/s^2", BaseU: PrefixMilli, Want: 9810, Dim: accel},
+		5: {S: "9.81m/s²", BaseU: PrefixMilli, Want: 9810, Dim: accel},
+		6: {S: "1e3 m^+1", BaseU: PrefixNone, Want: 1000, Dim: length},
+		// Prefix and unit disambiguation.
+		7:  {S: "5m", BaseU: PrefixMilli, Want: 5000, Dim: length},
+		8:  {S: "5mm", BaseU: PrefixMilli, Want: 5, Dim: length},
+		9:  {S: "5ms", BaseU: PrefixMicro, Want: 5000, Dim: timed},
+		10: {S: "5mol", BaseU: PrefixNone, Want: 5, Dim: amount},
+		11: {S: "5kkg", BaseU: PrefixNone, Want: 5000, Dim: mass},
+		12: {S: "5kg", BaseU: PrefixNone, Want: 5, Dim: mass},
+		13: {S: "2.5k", BaseU: PrefixNone, Want: 2500},
+		14: {S: "-2.5 μs", BaseU: PrefixNano, Want: -2500, Dim: timed},
+		15: {S: "2E", BaseU: PrefixPeta, Want: 2000},
+		// Partial reads.
+		16: {S: "2 k", BaseU: PrefixNone, Want: 2, N: 1},
+		17: {S: "5 m·x", BaseU: PrefixNone, Want: 5, Dim: length, N: 3},
+		18: {S: "5m·", BaseU: PrefixNone, Want: 5, Dim: length, N: 2},
+		19: {S: "0", BaseU: PrefixNone, Want: 0},
+		20: {S: "7 ", BaseU: PrefixNone, Want: 7, N: 1},
+		// Prefix is raised to the exponent of its unit.
+		21: {S: "1km²", BaseU: PrefixNone, Want: 1_000_000, Dim: area},
+		22: {S: "1 cm³", BaseU: PrefixMicro, Want: 1, Dim: volume},
+		23: {S: "1ks⁻¹", BaseU: PrefixMilli, Want: 1, Dim: freq},
+		24: {S: "2km^2·s", BaseU: PrefixNone, Want: 2_000_000, Dim: newdim([]int{2, 0, 1, 0, 0, 0, 0})},
+		25: {S: "3 mm/s", BaseU: PrefixMilli, Want: 3, Dim: newdim([]int{1, 0, -1, 0, 0, 0, 0})},
+	}
+	for i, test := range tests {
+		q, n, err := ParseQuantity(test.S, test.BaseU)
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		wantN := test.N
+		if wantN == 0 {
+			wantN = len(test.S)
+		}
+		if n != wantN {
+			t.Errorf("case %d: bytes read mismatch, got %d want %d", i, n, wantN)
+		}
+		if q.Fixed() != test.Want || q.Base() != test.BaseU || q.Dimension() != test.Dim {
+			t.Errorf("case %d: want %d%s %s, got %d%s %s from %q", i, test.Want, test.BaseU, test.Dim, q.Fixed(), q.Base(), q.Dimension(), test.S)
+		}
+	}
+}
+
+func TestParseQuantityErrors(t *testing.T) {
+	for i, s := range []string{
+		"",
+		"m",
+		"1..2m",
+		"5m^",
+		"5 m^-",
+		"5 s⁻",
+		"5 km^x",
+		"5 m^128",
+		"5 m¹²⁸",
+		"5 m^100·m^100",
+		"99999999999999999999",
+	} {
+		q, n, err := ParseQuantity(s, PrefixNone)
+		if err == nil {
+			t.Errorf("case %d: expected error, got %s from %q", i, q, s)
+		} else if n != 0 {
+			t.Errorf("case %d: expected no bytes read on error, got %d", i, n)
+		}
+	}
+}
+
+func TestParseQuantityErrorOffset(t *testing.T) {
+	var tests = []struct {
+		S      string
+		Kind   ParseErrorKind
+		Offset int
+	}{
+		0: {S: "5 m^-", Kind: ParseErrBadExponent, Offset: 5},
+		1: {S: "5km^x", Kind: ParseErrBadExponent, Offset: 4},
+		2: {S: "5 km·s⁻", Kind: ParseErrBadExponent, Offset: len("5 km·s⁻")},
+		3: {S: "1..2m", Kind: ParseErrDotDot, Offset: 2},
+		4: {S: "9e18k", Kind: ParseErrOverflow, Offset: 0},
+		5: {S: "5m^128", Kind: ParseErrExponentRange, Offset: 3},
+		6: {S: "5m^127·m", Kind: ParseErrExponentRange, Offset: len("5m^127·")},
+		7: {S: "5 m¹²⁸", Kind: ParseErrExponentRange, Offset: 3},
+		8: {S: "5 m^100/m^-100", Kind: ParseErrExponentRange, Offset: len("5 m^100/")},
+	}
+	for i, test := range tests {
+		_, _, err := ParseQuantity(test.S, PrefixNone)
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("case %d: expected *ParseError, got %T %v", i, err, err)
+			continue
+		}
+		if pe.Kind != test.Kind || pe.Offset != test.Offset || pe.Input != test.S {
+			t.Errorf("case %d: want %q at %d in %q, got %q at %d in %q", i, test.Kind, test.Offset, test.S, pe.Kind, pe.Offset, pe.Input)
+		}
+		if test.Kind == ParseErrExponentRange && !errors.Is(err, ErrDimOOB) {
+			t.Errorf("case %d: expected error to match ErrDimOOB", i)
+		}
+	}
+}
+
+func TestDimensionlessQuantityRoundTrip(t *testing.T) {
+	for exp := int(PrefixQuecto); exp <= int(PrefixQuetta); exp++ {
+		base := Prefix(exp)
+		if !base.IsValid() {
+			continue
+		}
+		for _, v := range []int64{1, -5, 25, 1500, 999_999} {
+			q, _ := NewQuantity(v, base, Dimension{})
+			s := q.String()
+			got, n, err := ParseQuantity(s, base)
+			if err != nil || n != len(s) || got != q {
+				t.Errorf("%d%s: round trip of %q got %d%s %s reading %d bytes (%v)", v, base, s, got.Fixed(), got.Base(), got.Dimension(), n, err)
+			}
+		}
+	}
+	for _, test := range []struct {
+		Q    Quantity
+		Want string
+	}{
+		{Q: Quantity{value: 5, base: PrefixMilli}, Want: "5e-03"},
+		{Q: Quantity{value: 1, base: PrefixTera}, Want: "1e+12"},
+		{Q: Quantity{value: 5, base: PrefixMicro}, Want: "5μ"},
+		{Q: Quantity{value: 5, base: PrefixMilli, dim: newdim([]int{1, 0, 0, 0, 0, 0, 0})}, Want: "5mm"},
+	} {
+		if got := test.Q.String(); got != test.Want {
+			t.Errorf("want %q, got %q", test.Want, got)
+		}
+	}
+}
+
+func TestQuantityFormatParseLoop(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		var exps [7]int
+		for j := range exps {
+			exps[j] = rng.Intn(7) - 3
+		}
+		base := Prefix(3 * (rng.Intn(21) - 10))
+		v := rng.Int63() >> uint(rng.Intn(63))
+		if rng.Intn(2) == 0 {
+			v = -v
+		}
+		q, err := NewQuantity(v, base, newdim(exps[:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := q.String()
+		got, n, err := ParseQuantity(s, base)
+		if err != nil {
+			t.Fatalf("%s: %q", err, s)
+		} else if n != len(s) {
+			t.Fatalf("mismatch number of bytes read got %d, want %d for %q", n, len(s), s)
+		}
+		if got != q {
+			t.Fatalf("format-parse loop failed for got %s, want %s for %q", got, q, s)
+		}
+	}
+}
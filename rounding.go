@@ -0,0 +1,87 @@
+package si
+
+import "errors"
+
+// RoundingMode selects how values are rounded when digits are discarded.
+// The zero value is [RoundHalfUp], used by the package's functions which take no rounding mode.
+type RoundingMode uint8
+
+const (
+	// RoundHalfUp rounds to the nearest value and ties away from zero: 2.5 -> 3, -2.5 -> -3.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value and ties to the even value: 2.5 -> 2, 3.5 -> 4.
+	RoundHalfEven
+	// RoundTowardZero truncates discarded digits: 2.9 -> 2, -2.9 -> -2.
+	RoundTowardZero
+	// RoundFloor rounds toward negative infinity: 2.9 -> 2, -2.1 -> -3.
+	RoundFloor
+	// RoundCeil rounds toward positive infinity: 2.1 -> 3, -2.9 -> -2.
+	RoundCeil
+	// RoundAwayFromZero rounds up the magnitude if any discarded digit is not zero: 2.1 -> 3, -2.1 -> -3.
+	RoundAwayFromZero
+	roundingModeInvalid
+)
+
+// Rounding errors.
+var (
+	ErrInvalidRoundingMode = errors.New("invalid rounding mode")
+	ErrOverflow            = errors.New("value overflows int64")
+)
+
+// IsValid returns true if m is one of the rounding modes defined by this package.
+func (m RoundingMode) IsValid() bool { return m < roundingModeInvalid }
+
+// String returns the name of the rounding mode, i.e: "HalfEven".
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfUp:
+		return "HalfUp"
+	case RoundHalfEven:
+		return "HalfEven"
+	case RoundTowardZero:
+		return "TowardZero"
+	case RoundFloor:
+		return "Floor"
+	case RoundCeil:
+		return "Ceil"
+	case RoundAwayFromZero:
+		return "AwayFromZero"
+	}
+	return "<invalid rounding mode>"
+}
+
+// roundUp reports whether a magnitude whose discarded part is being rounded should be
+// incremented. odd is the parity of the last kept digit, digit is the first discarded digit (0..9)
+// and sticky is true if any of the following discarded digits is not zero.
+func (m RoundingMode) roundUp(odd bool, digit byte, sticky, neg bool) bool {
+	switch m {
+	case RoundHalfEven:
+		return digit > 5 || digit == 5 && (sticky || odd)
+	case RoundTowardZero:
+		return false
+	case RoundFloor:
+		return neg && (digit != 0 || sticky)
+	case RoundCeil:
+		return !neg && (digit != 0 || sticky)
+	case RoundAwayFromZero:
+		return digit != 0 || sticky
+	}
+	return digit >= 5
+}
+
+// Rescale converts value expressed in units of from to units of to, rounding
+// discarded digits with mode. i.e: Rescale(1500, PrefixMicro, PrefixMilli, RoundHalfEven) returns 2.
+// It returns [ErrInvalidPrefix] if from or to are not valid, [ErrInvalidRoundingMode] if
+// mode is not valid and [ErrOverflow] if the result does not fit in an int64.
+func Rescale(value int64, from, to Prefix, mode RoundingMode) (int64, error) {
+	if !from.IsValid() || !to.IsValid() {
+		return 0, ErrInvalidPrefix
+	} else if !mode.IsValid() {
+		return 0, ErrInvalidRoundingMode
+	}
+	v, overflow := rescale(value, from, to, mode)
+	if overflow {
+		return 0, ErrOverflow
+	}
+	return v, nil
+}
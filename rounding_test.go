@@ -0,0 +1,125 @@
+package si
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRescale(t *testing.T) {
+	var tests = []struct {
+		V        int64
+		From, To Prefix
+		Want     [roundingModeInvalid]int64 // Indexed by rounding mode.
+	}{
+		0:  {V: 1500, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{2, 2, 1, 1, 2, 2}},
+		1:  {V: 2500, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{3, 2, 2, 2, 3, 3}},
+		2:  {V: -1500, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{-2, -2, -1, -2, -1, -2}},
+		3:  {V: -2500, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{-3, -2, -2, -3, -2, -3}},
+		4:  {V: 1501, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{2, 2, 1, 1, 2, 2}},
+		5:  {V: 1499, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{1, 1, 1, 1, 2, 2}},
+		6:  {V: -1, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{0, 0, 0, -1, 0, -1}},
+		7:  {V: 2000, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{2, 2, 2, 2, 2, 2}},
+		8:  {V: 0, From: PrefixMicro, To: PrefixMilli, Want: [6]int64{0, 0, 0, 0, 0, 0}},
+		9:  {V: 3, From: PrefixMilli, To: PrefixMicro, Want: [6]int64{3000, 3000, 3000, 3000, 3000, 3000}},
+		10: {V: 2, From: PrefixCenti, To: PrefixDeci, Want: [6]int64{0, 0, 0, 0, 1, 1}},
+		// Discarded digits after the first decide ties.
+		11: {V: 2_500_001, From: PrefixNano, To: PrefixMilli, Want: [6]int64{3, 3, 2, 2, 3, 3}},
+		12: {V: -2_500_001, From: PrefixNano, To: PrefixMilli, Want: [6]int64{-3, -3, -2, -3, -2, -3}},
+		13: {V: 1_000_500, From: PrefixNano, To: PrefixMilli, Want: [6]int64{1, 1, 1, 1, 2, 2}},
+		14: {V: 1, From: PrefixQuecto, To: PrefixQuetta, Want: [6]int64{0, 0, 0, 0, 1, 1}},
+		15: {V: math.MinInt64, From: PrefixNone, To: PrefixKilo, Want: [6]int64{-9223372036854776, -9223372036854776, -9223372036854775, -9223372036854776, -9223372036854775, -9223372036854776}},
+	}
+	for i, test := range tests {
+		for mode := RoundingMode(0); mode < roundingModeInvalid; mode++ {
+			got, err := Rescale(test.V, test.From, test.To, mode)
+			if err != nil {
+				t.Errorf("case %d %s: %s", i, mode, err)
+			} else if got != test.Want[mode] {
+				t.Errorf("case %d %s: want %d, got %d", i, mode, test.Want[mode], got)
+			}
+		}
+	}
+	var tests2 = []struct {
+		V        int64
+		From, To Prefix
+		Mode     RoundingMode
+		Err      error
+	}{
+		0: {V: 1, From: 4, To: PrefixMilli, Err: ErrInvalidPrefix},
+		1: {V: 1, From: PrefixMilli, To: 5, Err: ErrInvalidPrefix},
+		2: {V: 1, From: PrefixMilli, To: PrefixMicro, Mode: roundingModeInvalid, Err: ErrInvalidRoundingMode},
+		3: {V: math.MaxInt64, From: PrefixMilli, To: PrefixMicro, Err: ErrOverflow},
+		4: {V: math.MinInt64 / 10, From: PrefixDeca, To: PrefixDeci, Err: ErrOverflow},
+	}
+	for i, test := range tests2 {
+		_, err := Rescale(test.V, test.From, test.To, test.Mode)
+		if !errors.Is(err, test.Err) {
+			t.Errorf("error case %d: want %v, got %v", i, test.Err, err)
+		}
+	}
+}
+
+func TestParseFixedRound(t *testing.T) {
+	var tests = []struct {
+		S     string
+		BaseU Prefix
+		Want  [roundingModeInvalid]int64 // Indexed by rounding mode.
+	}{
+		0: {S: "2.5", BaseU: PrefixNone, Want: [6]int64{3, 2, 2, 2, 3, 3}},
+		1: {S: "3.5", BaseU: PrefixNone, Want: [6]int64{4, 4, 3, 3, 4, 4}},
+		2: {S: "-2.5", BaseU: PrefixNone, Want: [6]int64{-3, -2, -2, -3, -2, -3}},
+		3: {S: "2.51", BaseU: PrefixNone, Want: [6]int64{3, 3, 2, 2, 3, 3}},
+		4: {S: "-0.1", BaseU: PrefixNone, Want: [6]int64{0, 0, 0, -1, 0, -1}},
+		5: {S: "1500u", BaseU: PrefixMilli, Want: [6]int64{2, 2, 1, 1, 2, 2}},
+		6: {S: "2.5e-30", BaseU: PrefixNone, Want: [6]int64{0, 0, 0, 0, 1, 1}},
+		7: {S: "7k", BaseU: PrefixNone, Want: [6]int64{7000, 7000, 7000, 7000, 7000, 7000}},
+	}
+	for i, test := range tests {
+		for mode := RoundingMode(0); mode < roundingModeInvalid; mode++ {
+			got, n, err := ParseFixedRound(test.S, test.BaseU, mode)
+			if err != nil {
+				t.Errorf("case %d %s: %s", i, mode, err)
+			} else if got != test.Want[mode] || n != len(test.S) {
+				t.Errorf("case %d %s: want %d, got %d (read %d)", i, mode, test.Want[mode], got, n)
+			}
+		}
+	}
+	if _, _, err := ParseFixedRound("1", PrefixNone, roundingModeInvalid); err != ErrInvalidRoundingMode {
+		t.Error("expected invalid rounding mode error, got", err)
+	}
+}
+
+func TestAppendFixedRounding(t *testing.T) {
+	var tests = []struct {
+		V     int64
+		BaseU Prefix
+		Fmt   byte
+		Prec  int
+		Want  [roundingModeInvalid]string // Indexed by rounding mode.
+	}{
+		0: {V: 1250, BaseU: PrefixNone, Fmt: 'f', Prec: 2, Want: [6]string{"1.3k", "1.2k", "1.2k", "1.2k", "1.3k", "1.3k"}},
+		1: {V: -1250, BaseU: PrefixNone, Fmt: 'f', Prec: 2, Want: [6]string{"-1.3k", "-1.2k", "-1.2k", "-1.3k", "-1.2k", "-1.3k"}},
+		2: {V: 1350, BaseU: PrefixNone, Fmt: 'e', Prec: 2, Want: [6]string{"1.4e+03", "1.4e+03", "1.3e+03", "1.3e+03", "1.4e+03", "1.4e+03"}},
+		3: {V: 1251, BaseU: PrefixNone, Fmt: 'g', Prec: 2, Want: [6]string{"1.3k", "1.3k", "1.2k", "1.2k", "1.3k", "1.3k"}},
+		// Rounding carries over to the next prefix.
+		4: {V: 999_100, BaseU: PrefixMilli, Fmt: 'f', Prec: 3, Want: [6]string{"999", "999", "999", "999", "1k", "1k"}},
+		5: {V: -999_100, BaseU: PrefixMilli, Fmt: 'f', Prec: 3, Want: [6]string{"-999", "-999", "-999", "-1k", "-999", "-1k"}},
+		6: {V: 1001, BaseU: PrefixNone, Fmt: 'f', Prec: 1, Want: [6]string{"1k", "1k", "1k", "1k", "2k", "2k"}},
+	}
+	var buf [32]byte
+	for i, test := range tests {
+		for mode := RoundingMode(0); mode < roundingModeInvalid; mode++ {
+			got, err := FixedFormat{Fmt: test.Fmt, Prec: test.Prec, Rounding: mode}.AppendErr(buf[:0], test.V, test.BaseU)
+			if err != nil {
+				t.Errorf("case %d %s: %s", i, mode, err)
+			} else if string(got) != test.Want[mode] {
+				t.Errorf("case %d %s: want %s, got %s", i, mode, test.Want[mode], got)
+			}
+		}
+	}
+	got := FixedFormat{Fmt: 'f', Prec: 3, Rounding: roundingModeInvalid}.Append(buf[:0], 1, PrefixNone)
+	if string(got) != "<si!BAD ROUNDING>" {
+		t.Error("expected invalid rounding mode error, got", string(got))
+	}
+}
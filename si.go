@@ -3,7 +3,9 @@ package si
 import (
 	"errors"
 	"math"
+	"math/bits"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -13,14 +15,15 @@ const (
 	maxunit = math.MaxInt8
 )
 
-var errDimOOB = errors.New("dimension exceeds storage space (-127..127)")
+// ErrDimOOB is returned when a dimension exponent exceeds the storage space of a [Dimension].
+var ErrDimOOB = errors.New("dimension exceeds storage space (-127..127)")
 
 // NewDimension creates a new dimension from the given exponents.
 func NewDimension(Length, Mass, Time, Temperature, ElectricCurrent, Luminosity, Amount int) (Dimension, error) {
 	if isDimOOB(Length) || isDimOOB(Mass) || isDimOOB(Time) ||
 		isDimOOB(Temperature) || isDimOOB(ElectricCurrent) ||
 		isDimOOB(Luminosity) || isDimOOB(Amount) {
-		return Dimension{}, errDimOOB
+		return Dimension{}, ErrDimOOB
 	}
 	return Dimension{
 		dims: [7]dimint{
@@ -64,6 +67,7 @@ var exprune = [10]rune{
 
 var (
 	abstractDimFormatter, _ = NewDimensionFormatter(AbstractDimensionFormatterConfig())
+	siDimFormatter, _       = NewDimensionFormatter(derivedDimensionFormatterConfig())
 )
 
 // AbstractDimensionFormatConfig returns the abstract unit formatting configuration.
@@ -93,10 +97,29 @@ func DefaultDimensionFormatterConfig() DimensionFormatterConfig {
 	}
 }
 
+// derivedDimensionFormatterConfig returns the SI formatter config with the SI derived units
+// used to simplify dimensions. Becquerel, gray, sievert and katal are left out since they would
+// be used for any s⁻¹, m²·s⁻² or mol·s⁻¹ and remain opt-in through [DimensionFormatterConfig.DerivedUnits].
+// Degree Celsius is left out since it is offset from kelvin and parsing it as kelvin would silently misread temperatures.
+func derivedDimensionFormatterConfig() DimensionFormatterConfig {
+	cfg := DefaultDimensionFormatterConfig()
+	for _, u := range SIDerivedUnits() {
+		switch u.Symbol {
+		case "°C", "Bq", "Gy", "Sv", "kat":
+		default:
+			cfg.DerivedUnits = append(cfg.DerivedUnits, u)
+		}
+	}
+	cfg.Simplify = true
+	return cfg
+}
+
 // DimensionFormatter is an arrangement of unit representations.
 type DimensionFormatter struct {
-	fmts [7]string
-	sep  string
+	fmts     [7]string
+	sep      string
+	derived  []DerivedUnit
+	simplify bool
 }
 
 // DimensionFormatterConfig specifies how the DimensionFormatter will
@@ -112,6 +135,15 @@ type DimensionFormatterConfig struct {
 
 	// Unit separator.
 	Separator string
+
+	// DerivedUnits are named units the formatter uses to format dimensions that
+	// match them exactly and accepts when parsing, see [SIDerivedUnits].
+	// They are not used when nil.
+	DerivedUnits []DerivedUnit
+	// Simplify enables formatting dimensions with no exactly matching derived unit
+	// as a compact product of DerivedUnits and base units, i.e: "J·K⁻¹" or "N·m".
+	// Exact matches take precedence so torque is formatted as "N·m" only if joule is not in DerivedUnits.
+	Simplify bool
 }
 
 // NewDimensionFormatter creates a new dimension formatter.
@@ -119,9 +151,16 @@ func NewDimensionFormatter(cfg DimensionFormatterConfig) (*DimensionFormatter, e
 	if cfg.Length == "" || cfg.Mass == "" || cfg.Time == "" || cfg.Temperature == "" || cfg.Current == "" || cfg.Luminosity == "" || cfg.Amount == "" {
 		return nil, errors.New("empty format string")
 	}
+	for _, u := range cfg.DerivedUnits {
+		if u.Symbol == "" {
+			return nil, errors.New("empty derived unit symbol")
+		}
+	}
 
 	return &DimensionFormatter{
-		sep: cfg.Separator,
+		sep:      cfg.Separator,
+		derived:  append([]DerivedUnit(nil), cfg.DerivedUnits...),
+		simplify: cfg.Simplify,
 		fmts: [7]string{
 			0: cfg.Length,
 			1: cfg.Mass,
@@ -158,9 +197,11 @@ func (df *DimensionFormatter) StringDim(dim Dimension) string {
 
 // sizeofFormat returns exact size of the printed string of dim with df formatting.
 func (df *DimensionFormatter) sizeofFormat(dim Dimension) int {
+	plan := df.decompose(dim)
 	sizeof := 0
 	var printed bool
-	for i, exp := range dim.dims {
+	for i := 0; i < plan.nderived+len(plan.base); i++ {
+		symbol, exp := plan.term(df, i)
 		if exp == 0 {
 			// Exponent not printed.
 			continue
@@ -170,27 +211,35 @@ func (df *DimensionFormatter) sizeofFormat(dim Dimension) int {
 		}
 		printed = true
 		// Size of unit string added.
-		sizeof += len(df.fmts[i])
-		// Size of exponent in bytes, exponents are 2 bytes in length.
+		sizeof += len(symbol)
+		if exp == 1 {
+			continue // Exponent not printed.
+		}
+		// Size of exponent in bytes, superscript runes vary in length.
 		if exp < 0 {
-			sizeof += 2
+			sizeof += utf8.RuneLen(negexp)
+			exp = -exp
+		}
+		for ; exp > 0; exp /= 10 {
+			sizeof += utf8.RuneLen(exprune[exp%10])
 		}
-		digits := ilog10(int64(exp)) + 1
-		sizeof += digits * 2
 	}
 	return sizeof
 }
 
-// AppendFormat formats a dimension with
+// AppendFormat formats a dimension with df's unit symbols and appends it to b.
+// A dimension matching one of df's derived units is formatted with the derived unit symbol.
+// If df simplifies dimensions other dimensions may be formatted as a product of derived and base units.
 func (df *DimensionFormatter) AppendFormat(b []byte, dim Dimension) []byte {
 	// Size of this buffer should fit -32767 (MaxInt16)
 	if dim.IsDimensionless() {
 		return b
 	}
+	plan := df.decompose(dim)
 	var buf [8]byte
 	var lastPrinted bool
-	for i := range df.fmts {
-		dim := dim.dims[i]
+	for i := 0; i < plan.nderived+len(plan.base); i++ {
+		symbol, dim := plan.term(df, i)
 		if dim == 0 {
 			continue
 		}
@@ -198,7 +247,7 @@ func (df *DimensionFormatter) AppendFormat(b []byte, dim Dimension) []byte {
 			b = append(b, df.sep...)
 		}
 		lastPrinted = true
-		b = append(b, df.fmts[i]...)
+		b = append(b, symbol...)
 		if dim == 1 {
 			continue
 		}
@@ -218,6 +267,162 @@ func (df *DimensionFormatter) AppendFormat(b []byte, dim Dimension) []byte {
 	return b
 }
 
+// ParseDimension parses a dimension written with df's unit symbols, which is
+// the inverse of [DimensionFormatter.StringDim]. Terms are separated by df's separator, '·' or '*' and
+// are optionally followed by a superscript exponent "s⁻²" or an ASCII exponent "s^-2".
+// A '/' in place of a separator inverts the following term, so "m/s²" is read as "m·s⁻²".
+// Terms may be juxtaposed if df has no separator, i.e: "LM²T⁻³".
+// Parsing stops at the first character that is not part of the expression. Returns the parsed
+// dimension, the number of bytes consumed from the input and any error encountered during parsing.
+// Exponents outside of -127..127 return a [*ParseError] of kind [ParseErrExponentRange].
+func (df *DimensionFormatter) ParseDimension(s string) (dim Dimension, readBytes int, err error) {
+	var exps [7]int
+	for readBytes < len(s) {
+		pos := readBytes
+		inv := false
+		if pos != 0 {
+			n := df.separatorLen(s[pos:])
+			if n == 0 && s[pos] == '/' {
+				n = 1
+				inv = true
+			} else if n == 0 && df.sep != "" {
+				break
+			}
+			pos += n
+		}
+		start := pos
+		unit, n := df.matchUnit(s[pos:])
+		if n == 0 {
+			break
+		}
+		pos += n
+		exp, n, err := parseUnitExponent(s[pos:])
+		if err != nil {
+			return Dimension{}, 0, parseErrorAt(err, s, pos)
+		}
+		pos += n
+		if inv {
+			exp = -exp
+		}
+		for i := range exps {
+			exps[i] += exp * int(unit.dims[i])
+			if isDimOOB(exps[i]) {
+				return Dimension{}, 0, ErrExponentRange.at(s, start)
+			}
+		}
+		readBytes = pos
+	}
+	dim, _ = NewDimension(exps[0], exps[1], exps[2], exps[3], exps[4], exps[5], exps[6])
+	return dim, readBytes, nil
+}
+
+// prefixable reports whether a prefix may be attached to the first unit of dim as formatted by df.
+// This is the case if the unit is raised to the power of one and is not the kilogram, which is already prefixed.
+func (df *DimensionFormatter) prefixable(dim Dimension) bool {
+	var buf [64]byte
+	unit := string(df.AppendFormat(buf[:0], dim))
+	_, n := df.matchUnit(unit)
+	if n == 0 {
+		return true
+	}
+	exp, _, err := parseUnitExponent(unit[n:])
+	return err == nil && exp == 1 && unit[:n] != "kg"
+}
+
+// separatorLen returns the length of the unit separator s starts with or zero if there is none.
+func (df *DimensionFormatter) separatorLen(s string) int {
+	switch {
+	case df.sep != "" && strings.HasPrefix(s, df.sep):
+		return len(df.sep)
+	case strings.HasPrefix(s, "·"):
+		return len("·")
+	case strings.HasPrefix(s, "*"):
+		return 1
+	}
+	return 0
+}
+
+// matchUnit returns the dimension of the longest base or derived unit symbol s starts with and its length.
+func (df *DimensionFormatter) matchUnit(s string) (unit Dimension, n int) {
+	for i, sym := range df.fmts {
+		if len(sym) > n && strings.HasPrefix(s, sym) {
+			unit = Dimension{}
+			unit.dims[i] = 1
+			n = len(sym)
+		}
+	}
+	for _, u := range df.derived {
+		if len(u.Symbol) > n && strings.HasPrefix(s, u.Symbol) {
+			unit = u.Dim
+			n = len(u.Symbol)
+		}
+	}
+	return unit, n
+}
+
+// parseUnitExponent parses the exponent following a unit symbol. It returns an exponent
+// of 1 and reads no bytes if there is no exponent.
+func parseUnitExponent(s string) (exp, readBytes int, err error) {
+	neg := false
+	digits := 0
+	if strings.HasPrefix(s, "^") {
+		readBytes++
+		if readBytes < len(s) && (s[readBytes] == '-' || s[readBytes] == '+') {
+			neg = s[readBytes] == '-'
+			readBytes++
+		}
+		start := readBytes
+		for readBytes < len(s) && '0' <= s[readBytes] && s[readBytes] <= '9' {
+			exp = exp*10 + int(s[readBytes]-'0')
+			if exp > maxunit {
+				return 0, 0, ErrExponentRange.at(s, start)
+			}
+			digits++
+			readBytes++
+		}
+	} else {
+		r, n := utf8.DecodeRuneInString(s)
+		if r == negexp {
+			neg = true
+			readBytes += n
+		}
+		start := readBytes
+		for readBytes < len(s) {
+			r, n := utf8.DecodeRuneInString(s[readBytes:])
+			digit := superscriptDigit(r)
+			if digit < 0 {
+				break
+			}
+			exp = exp*10 + digit
+			if exp > maxunit {
+				return 0, 0, ErrExponentRange.at(s, start)
+			}
+			digits++
+			readBytes += n
+		}
+		if readBytes == 0 {
+			return 1, 0, nil // No exponent.
+		}
+	}
+	if digits == 0 {
+		return 0, 0, ErrBadExponent.at(s, readBytes)
+	}
+	if neg {
+		exp = -exp
+	}
+	return exp, readBytes, nil
+}
+
+// superscriptDigit returns the value of the superscript digit r or -1 if r is not a superscript digit.
+func superscriptDigit(r rune) int {
+	for i, sr := range exprune {
+		if r == sr {
+			return i
+		}
+	}
+	return -1
+}
+
 // String returns a human readable representation of the dimension using abstract unit letters (LMTKIJN).
 func (d Dimension) String() string {
 	return abstractDimFormatter.StringDim(d)
@@ -294,9 +499,16 @@ func DivDim(a, b Dimension) (Dimension, error) {
 // i.e: PrefixKilo corresponds to 'k' character used to denote a multiplier of 1000 to the unit it is prefixed to.
 type Prefix int8
 
-// Package unit prefix definitions.
+// Package unit prefix definitions. They span the full range of SI prefixes, 10⁻³⁰ to 10³⁰.
+// Keep in mind fixed-point values are stored in an int64 which holds up to 19 digits, so a single
+// value can span at most about 6 prefixes: a value in [PrefixQuecto] units can not exceed 9.2 [PrefixAtto]
+// base units and [PrefixQuetta] is only reachable from base units of [PrefixPeta] and above.
 const (
-	prefixInvalidMin Prefix = -21 + iota*3
+	prefixInvalidMin Prefix = -33 + iota*3
+	PrefixQuecto
+	PrefixRonto
+	PrefixYocto
+	PrefixZepto
 	PrefixAtto
 	PrefixFemto
 	PrefixPico
@@ -310,37 +522,61 @@ const (
 	PrefixTera
 	PrefixPeta
 	PrefixExa
+	PrefixZetta
+	PrefixYotta
+	PrefixRonna
+	PrefixQuetta
 	prefixInvalidMax
 )
 
-// Prefix errors.
+// Non-engineering SI prefixes. Their exponents are not multiples of 3 and they are
+// only used when formatting if requested, see [FixedFormat].
+const (
+	PrefixCenti Prefix = -2
+	PrefixDeci  Prefix = -1
+	PrefixDeca  Prefix = 1
+	PrefixHecto Prefix = 2
+)
+
+// Prefix errors. ErrInvalidPrefix is returned by functions which take an invalid base units prefix,
+// the others by [ExponentToPrefix].
 var (
-	errPrefixNotMod3  = errors.New("SI prefix must be multiple of 3")
-	errPrefixTooLarge = errors.New("SI prefix too large to represent")
-	errPrefixTooSmall = errors.New("SI prefix too small/negative to represent")
+	ErrPrefixNotMod3  = errors.New("SI prefix must be multiple of 3 or between -2 and 2")
+	ErrPrefixTooLarge = errors.New("SI prefix too large to represent")
+	ErrPrefixTooSmall = errors.New("SI prefix too small/negative to represent")
+	ErrInvalidPrefix  = errors.New("invalid SI prefix")
 )
 
 // ExponentToPrefix converts exponent to a SI prefix.
-// Exponent must be modulus of 3 and representable by this package's type.
+// Exponent must be modulus of 3 or between -2 and 2 and representable by this package's type.
 // i.e:
 //   - -3 returns [PrefixMilli]
 //   - 3 returns [PrefixKilo]
 //   - 0 returns [PrefixNone]
+//   - -2 returns [PrefixCenti]
 func ExponentToPrefix(exp int) (pfx Prefix, err error) {
-	if exp%3 != 0 {
-		return pfx, errPrefixNotMod3
-	} else if pfx >= prefixInvalidMax {
-		return pfx, errPrefixTooLarge
-	} else if pfx <= prefixInvalidMin {
-		return pfx, errPrefixTooSmall
+	if exp%3 != 0 && (exp < -2 || exp > 2) {
+		return pfx, ErrPrefixNotMod3
+	} else if exp >= int(prefixInvalidMax) {
+		return pfx, ErrPrefixTooLarge
+	} else if exp <= int(prefixInvalidMin) {
+		return pfx, ErrPrefixTooSmall
 	}
 	return Prefix(exp), nil
 }
 
 // RuneToPrefix interprets the argument rune as an SI prefix.
-// It does not parse PrefixNone.
+// It does not parse PrefixNone nor [PrefixDeca] which is represented by two characters, see [ParsePrefix].
 func RuneToPrefix(r rune) (pfx Prefix, err error) {
 	switch r {
+	case 'q':
+		pfx = PrefixQuecto
+	case 'r':
+		pfx = PrefixRonto
+	case 'y':
+		pfx = PrefixYocto
+	case 'z':
+		pfx = PrefixZepto
 	case 'a':
 		pfx = PrefixAtto
 	case 'f':
@@ -353,6 +589,12 @@ func RuneToPrefix(r rune) (pfx Prefix, err error) {
 		pfx = PrefixMicro
 	case 'm':
 		pfx = PrefixMilli
+	case 'c':
+		pfx = PrefixCenti
+	case 'd':
+		pfx = PrefixDeci
+	case 'h':
+		pfx = PrefixHecto
 	case 'k':
 		pfx = PrefixKilo
 	case 'M':
@@ -365,25 +607,57 @@ func RuneToPrefix(r rune) (pfx Prefix, err error) {
 		pfx = PrefixPeta
 	case 'E':
 		pfx = PrefixExa
+	case 'Z':
+		pfx = PrefixZetta
+	case 'Y':
+		pfx = PrefixYotta
+	case 'R':
+		pfx = PrefixRonna
+	case 'Q':
+		pfx = PrefixQuetta
 	default:
-		err = errUnknownPrefix
+		err = ErrUnknownPrefix
 	}
 	return pfx, err
 }
 
+// ParsePrefix parses the SI prefix at the start of s, including the two character
+// deca prefix "da". It does not parse PrefixNone.
+// Returns the prefix, the number of bytes consumed from the input and any error encountered during parsing.
+func ParsePrefix(s string) (pfx Prefix, readBytes int, err error) {
+	if strings.HasPrefix(s, "da") {
+		return PrefixDeca, 2, nil
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	pfx, err = RuneToPrefix(r)
+	if err != nil {
+		return 0, 0, ErrUnknownPrefix.at(s, 0)
+	}
+	return pfx, n, nil
+}
+
 // IsValid checks if the prefix is one of the supported standard SI prefixes or the zero base prefix.
 func (p Prefix) IsValid() bool {
-	return p == PrefixNone || p.Character() != ' '
+	return p == PrefixNone || p.String()[0] != '<'
 }
 
 // String returns a human readable representation of the Prefix of string type.
 // Returns a error message string if Prefix is undefined. Guarateed to return non-zero string.
 func (p Prefix) String() string {
-	if p == PrefixMicro {
+	switch p {
+	case PrefixMicro:
 		return "μ"
+	case PrefixCenti:
+		return "c"
+	case PrefixDeci:
+		return "d"
+	case PrefixDeca:
+		return "da"
+	case PrefixHecto:
+		return "h"
 	}
-	const pfxTable = "a!!f!!p!!n!!u!!m!! !!k!!M!!G!!T!!E"
-	offset := int(p - PrefixAtto)
+	const pfxTable = "q!!r!!y!!z!!a!!f!!p!!n!!u!!m!! !!k!!M!!G!!T!!P!!E!!Z!!Y!!R!!Q"
+	offset := int(p - PrefixQuecto)
 	if offset < 0 || offset >= len(pfxTable) || pfxTable[offset] == '!' {
 		return "<si!invalid Prefix>"
 	}
@@ -397,102 +671,45 @@ func (p Prefix) Exponent() (exp int) {
 }
 
 // Character returns the single character SI representation of the unit prefix.
-// If not representable or invalid returns space caracter ' '.
+// If not representable, as is the case of [PrefixDeca], or invalid returns space caracter ' '.
 func (p Prefix) Character() (s rune) {
 	if p == PrefixMicro {
 		return 'μ'
 	}
 	s = rune(p.String()[0])
-	if s == '<' {
+	if s == '<' || p == PrefixDeca {
 		s = ' '
 	}
 	return s
 }
 
 // AppendFixed formats a fixed-point number with a given magnitude base units and
-// appends it's representation to the argument buffer.
+// appends it's representation to the argument buffer. The value is formatted with
+// prec significant digits, rounding half away from zero, see [AppendFixedRound]. The 'f' fmt uses engineering prefixes
+// and formats values of at most 3 digits in full. The 'e' fmt uses scientific notation and 'g'
+// uses 'f' within the [PrefixAtto] to [PrefixExa] range and 'e' otherwise. See [FixedFormat] for more options.
+// Errors are written to the buffer as a string starting with "<si!", use [AppendFixedErr] to handle them.
 //
-//	"123.456k" for value=123456, baseUnits=PrefixNone, prec=6
-//	"123k" for value=123456, baseUnits=PrefixNone, prec=3
+//	"123.456k" for value=123456, baseUnits=PrefixNone, fmt='f', prec=6
+//	"123k" for value=123456, baseUnits=PrefixNone, fmt='f', prec=3
+//	"120k" for value=123456, baseUnits=PrefixNone, fmt='f', prec=2
+//	"1.2e+05" for value=123456, baseUnits=PrefixNone, fmt='e', prec=2
+//	"1.23e+21" for value=1234, baseUnits=PrefixExa, fmt='g', prec=3
 func AppendFixed(b []byte, value int64, baseUnits Prefix, fmt byte, prec int) []byte {
-	switch {
-	case fmt != 'f':
-		return append(b, "<si!INVALID FMT>"...)
-	case prec <= 0:
-		return append(b, "<si!LESS-EQ-ZERO PREC>"...)
-	case !baseUnits.IsValid():
-		return append(b, "<si!BAD BASE>"...)
-	case prec >= 21:
-		return append(b, "<si!LARGE PREC>"...)
-	case value == 0:
-		return append(b, '0')
-	}
-
-	isNegative := value < 0
-	if isNegative {
-		value = -value
-	}
-	v64 := int64(value)
-	log10 := ilog10(v64)
-
-	log10mod3 := log10 % 3
-	frontDigits := (log10mod3) + 1
-	backDigits := log10 - log10mod3
-	if log10 < backDigits {
-		frontDigits = 0
-	}
-
-	// We now prepare the value by trimming digits after the precision cutoff.
-	// We need to trim when excess > 0.
-	// TODO: Decide on whether to keep forced 3-sigfig formatting when only at 3 digits corresponding to `backDigits != 0` condition.
-	excess := backDigits + frontDigits - prec
-	if excess > 0 && backDigits != 0 {
-		x := v64 % powerOf10[excess]
-		rlim := iLogRoundTable[excess]
-		roundUp := x >= rlim
-		v64 /= powerOf10[excess]
-		v64 += int64(b2i(roundUp))
-		newIlog10 := ilog10(v64) + excess
-		if newIlog10 != log10 {
-			// Rounding where frontdigits overflow.
-			log10 = newIlog10
-			log10mod3 = log10 % 3
-			frontDigits = log10mod3 + 1
-			backDigits = log10 - log10mod3
-			if log10 < backDigits {
-				frontDigits = 0
-			}
-		}
-	}
-	// Calculate new base.
-	baseUnits += Prefix(log10 - log10mod3)
-	if baseUnits > PrefixExa {
-		return append(b, "<si!UNREPRESENTABLE PREFIX>"...)
-	}
-
-	if isNegative {
-		b = append(b, '-')
-	}
-
-	var buf [20]byte
-	prevlen := len(b)
-	b = strconv.AppendInt(b, v64, 10)
-	last := append(buf[:0], b[prevlen+frontDigits:]...)
-	b = b[:prevlen+frontDigits]
+	return FixedFormat{Fmt: fmt, Prec: prec}.Append(b, value, baseUnits)
+}
 
-	for i := range last {
-		// Only print if has non-zero part.
-		if last[i] != '0' {
-			b = append(b[:prevlen+frontDigits], '.')
-			b = append(b, last...)
-			break
-		}
-	}
+// AppendFixedRound is like [AppendFixed] but discards digits with the rounding mode,
+// i.e: value=1250, baseUnits=PrefixNone, fmt='f', prec=2 is formatted as "1.2k" with [RoundHalfEven].
+func AppendFixedRound(b []byte, value int64, baseUnits Prefix, fmt byte, prec int, mode RoundingMode) []byte {
+	return FixedFormat{Fmt: fmt, Prec: prec, Rounding: mode}.Append(b, value, baseUnits)
+}
 
-	if baseUnits != PrefixNone {
-		b = append(b, baseUnits.String()...)
-	}
-	return b
+// AppendFixedErr is like [AppendFixed] but returns an error instead of writing it to the buffer.
+// On error b is returned unmodified and the error is one of [ErrInvalidFormat], [ErrPrecisionTooSmall],
+// [ErrPrecisionTooLarge], [ErrInvalidBase] or [ErrUnrepresentable].
+func AppendFixedErr(b []byte, value int64, baseUnits Prefix, fmt byte, prec int) ([]byte, error) {
+	return FixedFormat{Fmt: fmt, Prec: prec}.AppendErr(b, value, baseUnits)
 }
 
 // FixedToFloat converts a fixed-point integer representation to a floating point number.
@@ -512,7 +729,7 @@ func FixedToFloat(fixedValue int64, baseUnits Prefix) float64 {
 //
 // Supported input formats:
 //   - Simple numbers: "123", "0.456"
-//   - With SI prefix: "2k", "3.5M", "100m"
+//   - With SI prefix: "2k", "3.5M", "100m", "2.5c", "3da"
 //   - Exponent notation (lowercase): "2e5", "1.5e3k", "3e-2m"
 //   - Exponent notation (uppercase): "2E5", "1.5E3k", "3E-2m"
 //   - With explicit sign: "+123", "-456", "2e+5"
@@ -526,24 +743,82 @@ func FixedToFloat(fixedValue int64, baseUnits Prefix) float64 {
 //
 // The function maintains fixed-point precision throughout the conversion process.
 // Returns the parsed value in baseUnits, the number of bytes consumed from the input,
-// and any error encountered during parsing.
+// and any error encountered during parsing. Parsing errors are of type [*ParseError] and
+// can be matched with [errors.Is] against the package's parsing errors, i.e: [ErrUnknownPrefix].
+// [ErrInvalidPrefix] is returned if baseUnits is not valid. Digits finer than baseUnits are
+// rounded half away from zero, use [ParseFixedRound] to select the rounding mode.
 func ParseFixed(s string, baseUnits Prefix) (value int64, readBytes int, err error) {
-	var buf [20]byte
+	return ParseFixedRound(s, baseUnits, RoundHalfUp)
+}
+
+// ParseFixedRound is like [ParseFixed] but rounds digits finer than baseUnits with mode,
+// i.e: "2.5" parsed in [PrefixNone] base units is 2 with [RoundHalfEven] and 3 with [RoundCeil].
+// [ErrInvalidRoundingMode] is returned if mode is not valid.
+func ParseFixedRound(s string, baseUnits Prefix, mode RoundingMode) (value int64, readBytes int, err error) {
+	if !baseUnits.IsValid() {
+		return 0, 0, ErrInvalidPrefix
+	} else if !mode.IsValid() {
+		return 0, 0, ErrInvalidRoundingMode
+	}
+	d, readBytes, err := parseDecimal(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	var incomingPrefix Prefix
+	if readBytes < len(s) {
+		var n int
+		incomingPrefix, n, err = ParsePrefix(s[readBytes:])
+		if err != nil {
+			return 0, 0, parseErrorAt(err, s, readBytes)
+		}
+		readBytes += n
+	}
+	v, overflow := dtoi(d, int(incomingPrefix-baseUnits), mode)
+	if overflow {
+		return 0, 0, d.overflowErr().at(s, 0)
+	}
+	return v, readBytes, nil
+}
+
+// parseDecimal parses the numeric part of a decimal point representation with optional
+// sign and exponent notation. It stops at the first character that is not part of the number.
+func parseDecimal(s string) (d decimal, readBytes int, err error) {
+	var buf [19]byte // Up to 19 significant digits, which always fit in a uint64.
+	d, digits, readBytes, err := scanDecimal(s, buf[:])
+	if err != nil {
+		return decimal{}, 0, err
+	}
+	if len(digits) > 0 { // digits is empty when all digits are leading zeros.
+		d.base, err = strconv.ParseUint(string(digits), 10, 64)
+		if err != nil {
+			return decimal{}, 0, d.overflowErr().at(s, 0)
+		}
+	}
+	return d, readBytes, nil
+}
+
+// scanDecimal scans the number at the start of s as in [parseDecimal]. The significant digits
+// are stored in buf and returned as digits, the sign and exponent are returned in d.
+// An error is returned if there are more significant digits than fit in buf.
+func scanDecimal(s string, buf []byte) (d decimal, digits []byte, readBytes int, err error) {
 	// s indices.
 	var dotPos, wholeEnd, bufPtr int = -1, 0, 0
-	var seenPlus bool
-	var d decimal
+	var seenPlus, seenDigit bool
 CHARLOOP:
 	for wholeEnd < len(s) {
 		c := s[wholeEnd]
 		if '0' <= c && c <= '9' {
-			if bufPtr >= len(buf) {
-				err = errOverflowsInt64
-				break CHARLOOP
-			} else if bufPtr == 0 && dotPos < 0 && c == '0' {
-				// Skip zeros preceding decimal point.
+			seenDigit = true
+			if bufPtr == 0 && c == '0' {
+				// Skip leading zeros.
+				if dotPos >= 0 {
+					d.exp--
+				}
 				wholeEnd++
 				continue
+			} else if bufPtr >= len(buf) {
+				err = d.overflowErr()
+				break CHARLOOP
 			}
 			buf[bufPtr] = c
 			bufPtr++
@@ -557,31 +832,31 @@ CHARLOOP:
 		switch c {
 		case '.':
 			if dotPos >= 0 {
-				err = errDotDot
+				err = ErrDotDot
 				break CHARLOOP
 			}
 			dotPos = wholeEnd
 		case '+':
 			if seenPlus {
-				err = errPlusPlus
+				err = ErrPlusPlus
 				break CHARLOOP
 			} else if d.neg {
-				err = errPlusMinus
+				err = ErrPlusMinus
 				break CHARLOOP
 			} else if wholeEnd != 0 {
-				err = errNaN
+				err = ErrNaN
 				break CHARLOOP
 			}
 			seenPlus = true
 		case '-':
 			if d.neg {
-				err = errMinusMinus
+				err = ErrMinusMinus
 				break CHARLOOP
 			} else if seenPlus {
-				err = errPlusPlus
+				err = ErrPlusPlus
 				break CHARLOOP
 			} else if wholeEnd != 0 {
-				err = errNaN
+				err = ErrNaN
 				break CHARLOOP
 			}
 			d.neg = true
@@ -591,7 +866,7 @@ CHARLOOP:
 		wholeEnd++
 	}
 	if err != nil {
-		return 0, 0, err
+		return decimal{}, nil, 0, parseErrorAt(err, s, wholeEnd)
 	}
 	readBytes = wholeEnd
 
@@ -604,12 +879,12 @@ CHARLOOP:
 			nextChar := s[readBytes+1]
 			isExpChar := ('0' <= nextChar && nextChar <= '9') || nextChar == '+' || nextChar == '-'
 			if !isExpChar {
-				// Not exponent notation, let prefix parser handle it.
-				goto PREFIX_PARSE
+				// Not exponent notation, let caller handle it as prefix.
+				goto DIGITS
 			}
 		} else {
 			// 'e' or 'E' at end of string, not exponent notation.
-			goto PREFIX_PARSE
+			goto DIGITS
 		}
 
 		readBytes++ // skip 'e' or 'E'
@@ -621,30 +896,33 @@ CHARLOOP:
 			expNeg = true
 			readBytes++
 			if readBytes >= len(s) {
-				return 0, 0, errNaN
+				return decimal{}, nil, 0, ErrNaN.at(s, readBytes)
 			}
 		case '+':
 			readBytes++
 			if readBytes >= len(s) {
-				return 0, 0, errNaN
+				return decimal{}, nil, 0, ErrNaN.at(s, readBytes)
 			}
 		}
 
-		// Parse exponent digits.
+		// Parse exponent digits. The exponent is clamped so that adding it to
+		// d.exp cannot overflow; clamped values are far outside the int64 range.
 		expStart := readBytes
+		expVal := 0
 		for readBytes < len(s) && '0' <= s[readBytes] && s[readBytes] <= '9' {
+			if expVal < maxParsedExp {
+				expVal = expVal*10 + int(s[readBytes]-'0')
+			}
 			readBytes++
 		}
 
 		if readBytes == expStart {
-			return 0, 0, errNaN
+			return decimal{}, nil, 0, ErrNaN.at(s, readBytes)
 		}
 
-		expVal, err := strconv.Atoi(s[expStart:readBytes])
-		if err != nil {
-			return 0, 0, errOverflowsInt64
+		if expVal > maxParsedExp {
+			expVal = maxParsedExp
 		}
-
 		if expNeg {
 			expVal = -expVal
 		}
@@ -652,30 +930,11 @@ CHARLOOP:
 		d.exp += expVal
 	}
 
-PREFIX_PARSE:
-
-	var incomingPrefix Prefix
-	if readBytes < len(s) {
-		r, n := utf8.DecodeRuneInString(s[readBytes:])
-		incomingPrefix, err = RuneToPrefix(r)
-		if err != nil {
-			return 0, 0, err
-		}
-		readBytes += n
-	}
-	// Calculate exponent modifier from decimal point.
-	// Where bufPtr is length of number, dotPos is position of decimal w.r.t start.
-	//  xxx.xxxxxx gives dotPos=3, bufPtr=3+6 -> exp=-6
-	d.base, err = strconv.ParseUint(string(buf[:bufPtr]), 10, 64)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	v, overflow := dtoi(d, int(incomingPrefix-baseUnits))
-	if overflow {
-		return 0, 0, errOverflowsInt64
+DIGITS:
+	if !seenDigit {
+		return decimal{}, nil, 0, ErrNaN.at(s, readBytes)
 	}
-	return v, readBytes, nil
+	return d, buf[:bufPtr], readBytes, nil
 }
 
 // ilog10 returns the integer logarithm base 10 of v, which
@@ -686,7 +945,7 @@ func ilog10(v int64) int {
 			return i - 1
 		}
 	}
-	return len(powerOf10)
+	return len(powerOf10) - 1
 }
 
 var powerOf10 = [...]int64{
@@ -711,29 +970,6 @@ var powerOf10 = [...]int64{
 	1_000_000_000_000_000_000,
 }
 
-var iLogRoundTable = [...]int64{
-	0,
-	5,
-	50,
-	500,
-	5_000,
-	50_000,
-	500_000,
-	5_000_000,
-	50_000_000,
-	500_000_000,
-	5_000_000_000,
-	50_000_000_000,
-	500_000_000_000,
-	5_000_000_000_000,
-	50_000_000_000_000,
-	500_000_000_000_000,
-	5_000_000_000_000_000,
-	50_000_000_000_000_000,
-	500_000_000_000_000_000,
-	5_000_000_000_000_000_000,
-}
-
 func b2i(b bool) int {
 	if b {
 		return 1
@@ -741,12 +977,82 @@ func b2i(b bool) int {
 	return 0
 }
 
+// scale128 multiplies the 128-bit magnitude hi:lo by 10^exp. Negative exponents
+// divide the magnitude rounding with mode, neg being the sign of the value. Returns false on overflow.
+func scale128(hi, lo uint64, neg bool, exp int, mode RoundingMode) (uint64, uint64, bool) {
+	if exp >= 0 {
+		return mul128Pow10(hi, lo, exp)
+	}
+	var rem uint64
+	var sticky bool
+	for i := 0; i < -exp; i++ {
+		sticky = sticky || rem != 0
+		if hi == 0 && lo == 0 {
+			rem = 0 // Remaining discarded digits are zero.
+			break
+		}
+		var qhi uint64
+		qhi, rem = bits.Div64(0, hi, 10)
+		lo, rem = bits.Div64(rem, lo, 10)
+		hi = qhi
+	}
+	if mode.roundUp(lo&1 == 1, byte(rem), sticky, neg) {
+		var carry uint64
+		lo, carry = bits.Add64(lo, 1, 0)
+		hi += carry
+	}
+	return hi, lo, true
+}
+
+// mul128Pow10 multiplies the 128-bit magnitude hi:lo by 10^exp. Returns false on overflow.
+func mul128Pow10(hi, lo uint64, exp int) (uint64, uint64, bool) {
+	for i := 0; i < exp; i++ {
+		if hi == 0 && lo == 0 {
+			break
+		}
+		h1, l1 := bits.Mul64(lo, 10)
+		h2, l2 := bits.Mul64(hi, 10)
+		var carry uint64
+		hi, carry = bits.Add64(h1, l2, 0)
+		if h2 != 0 || carry != 0 {
+			return 0, 0, false
+		}
+		lo = l1
+	}
+	return hi, lo, true
+}
+
+// uabs returns the magnitude of v and whether v is negative.
+func uabs(v int64) (u uint64, neg bool) {
+	if v < 0 {
+		return -uint64(v), true
+	}
+	return uint64(v), false
+}
+
+// toInt64 returns the signed representation of magnitude u. Returns false if it overflows.
+func toInt64(u uint64, neg bool) (int64, bool) {
+	if neg {
+		if u > 1<<63 {
+			return 0, false
+		}
+		return -int64(u), true
+	}
+	if u > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(u), true
+}
+
 /*
 Logic below ripped directly from https://github.com/periph/conn/blob/main/physic/units.go
 Modified to avoid ongoing heap allocations and export ParseError for user convenience.
 */
 
 // Decimal is the representation of decimal number.
+// maxParsedExp is the largest magnitude of a parsed exponent in exponent notation.
+const maxParsedExp = 1 << 20
+
 type decimal struct {
 	// base hold the significant digits.
 	base uint64
@@ -755,26 +1061,130 @@ type decimal struct {
 	// neg it true if the number is negative.
 	neg bool
 }
+
+// overflowErr returns the error for d exceeding the range of an int64.
+func (d decimal) overflowErr() *ParseError {
+	if d.neg {
+		return ErrOverflowsInt64Negative
+	}
+	return ErrOverflowsInt64
+}
+
+// ParseError is returned by the parsing functions of this package. It locates
+// the offending character in the input so it can be reported to users.
 type ParseError struct {
-	error
+	// Kind is the cause of the error.
+	Kind ParseErrorKind
+	// Offset is the byte offset into Input of the offending character.
+	// It is equal to len(Input) if the input ended unexpectedly.
+	Offset int
+	// Input is the string being parsed.
+	Input string
 }
 
-func makeParseError(s string) *ParseError {
-	return &ParseError{error: errors.New(s)}
+// ParseErrorKind enumerates the causes of a [ParseError].
+type ParseErrorKind uint8
+
+// Parse error kinds.
+const (
+	_ ParseErrorKind = iota
+	ParseErrPlusMinus
+	ParseErrMinusMinus
+	ParseErrNaN
+	ParseErrPlusPlus
+	ParseErrDotDot
+	ParseErrOverflow
+	ParseErrNegativeOverflow
+	ParseErrUnknownPrefix
+	ParseErrBadExponent
+	ParseErrExponentRange
+)
+
+// String returns a description of the error kind, i.e: "not a number".
+func (k ParseErrorKind) String() string {
+	switch k {
+	case ParseErrPlusMinus:
+		return "contains both plus and minus"
+	case ParseErrMinusMinus:
+		return "contains multiple minus symbols"
+	case ParseErrNaN:
+		return "not a number"
+	case ParseErrPlusPlus:
+		return "contains multiple plus symbols"
+	case ParseErrDotDot:
+		return "contains multiple decimal points"
+	case ParseErrOverflow:
+		return "exceeds maximum"
+	case ParseErrNegativeOverflow:
+		return "exceeds minimum"
+	case ParseErrUnknownPrefix:
+		return "unknown SI prefix"
+	case ParseErrBadExponent:
+		return "invalid unit exponent"
+	case ParseErrExponentRange:
+		return "unit exponent exceeds storage space (-127..127)"
+	}
+	return "<si!invalid ParseErrorKind>"
 }
 
-func (pe *ParseError) Unwrap() error { return pe.error }
+// Error returns the description of the error kind followed by the input and the offset of the offending character.
+// i.e: `unknown SI prefix at offset 3 in "3.3x"`.
+func (pe *ParseError) Error() string {
+	if pe.Input == "" {
+		return pe.Kind.String()
+	}
+	return pe.Kind.String() + " at offset " + strconv.Itoa(pe.Offset) + " in " + strconv.Quote(pe.Input)
+}
+
+// Is reports whether target is a [*ParseError] of the same kind with no input, such as
+// the package's parsing errors, or if target is equal to pe.
+func (pe *ParseError) Is(target error) bool {
+	t, ok := target.(*ParseError)
+	if !ok || t.Kind != pe.Kind {
+		return false
+	}
+	return (t.Input == "" && t.Offset == 0) || *t == *pe
+}
+
+// Unwrap returns [ErrDimOOB] for errors of kind [ParseErrExponentRange] so they match it with [errors.Is].
+func (pe *ParseError) Unwrap() error {
+	if pe.Kind == ParseErrExponentRange {
+		return ErrDimOOB
+	}
+	return nil
+}
+
+// at returns a copy of pe for input s with the offending character at offset.
+func (pe *ParseError) at(s string, offset int) *ParseError {
+	return &ParseError{Kind: pe.Kind, Offset: offset, Input: s}
+}
+
+// parseErrorAt relocates err to input s if it is a [*ParseError] found while parsing s[offset:].
+// Other errors are returned as-is.
+func parseErrorAt(err error, s string, offset int) error {
+	if pe, ok := err.(*ParseError); ok {
+		return pe.at(s, offset+pe.Offset)
+	}
+	return err
+}
 
-// parsing errors.
+// Parsing errors. They hold no input and match any [*ParseError] of the same
+// kind when used as the target of [errors.Is]:
+//
+//	if errors.Is(err, si.ErrUnknownPrefix) {
+//		// Handle unknown prefix.
+//	}
 var (
-	errPlusMinus              = makeParseError("contains both plus and minus")
-	errMinusMinus             = makeParseError("contains multiple minus symbols")
-	errNaN                    = makeParseError("not a number")
-	errPlusPlus               = makeParseError("contains multiple plus symbols")
-	errDotDot                 = makeParseError("contains multiple decimal points")
-	errOverflowsInt64         = makeParseError("exceeds maximum")
-	errOverflowsInt64Negative = makeParseError("exceeds minimum")
-	errUnknownPrefix          = makeParseError("unknown SI prefix")
+	ErrPlusMinus              = &ParseError{Kind: ParseErrPlusMinus}
+	ErrMinusMinus             = &ParseError{Kind: ParseErrMinusMinus}
+	ErrNaN                    = &ParseError{Kind: ParseErrNaN}
+	ErrPlusPlus               = &ParseError{Kind: ParseErrPlusPlus}
+	ErrDotDot                 = &ParseError{Kind: ParseErrDotDot}
+	ErrOverflowsInt64         = &ParseError{Kind: ParseErrOverflow}
+	ErrOverflowsInt64Negative = &ParseError{Kind: ParseErrNegativeOverflow}
+	ErrUnknownPrefix          = &ParseError{Kind: ParseErrUnknownPrefix}
+	ErrBadExponent            = &ParseError{Kind: ParseErrBadExponent}
+	ErrExponentRange          = &ParseError{Kind: ParseErrExponentRange}
 )
 
 // Converts from decimal to int64.
@@ -783,39 +1193,13 @@ var (
 // in powers of ten.
 //
 // Returns true if the value overflowed.
-func dtoi(d decimal, scale int) (int64, bool) {
-	// Get the total magnitude of the number.
-	// a^x * b^y = a*b^(x+y) since scale is of the order unity this becomes
-	// 1^x * b^y = b^(x+y).
-	// mag must be positive to use as index in to powerOf10 array.
-	u := d.base
-	mag := d.exp + scale
-	if mag < 0 {
-		mag = -mag
-	}
-	var n int64
-	if mag > 18 {
+func dtoi(d decimal, scale int, mode RoundingMode) (int64, bool) {
+	// Scaling is done with 128 bit precision so that values which round
+	// to zero or fit after scaling are not reported as overflowing.
+	hi, lo, ok := scale128(0, d.base, d.neg, d.exp+scale, mode)
+	if !ok || hi != 0 {
 		return 0, true
 	}
-	// Divide is = 10^(-mag)
-	switch {
-	case d.exp+scale < 0:
-		u = (u + uint64(powerOf10[mag])/2) / uint64(powerOf10[mag])
-	case mag == 0:
-		if u > math.MaxInt64 {
-			return 0, true
-		}
-	default:
-		check := u * uint64(powerOf10[mag])
-		if check/uint64(powerOf10[mag]) != u || check > math.MaxInt64 {
-			return 0, true
-		}
-		u *= uint64(powerOf10[mag])
-	}
-
-	n = int64(u)
-	if d.neg {
-		n = -n
-	}
-	return n, false
+	n, ok := toInt64(lo, d.neg)
+	return n, !ok
 }
@@ -1,16 +1,24 @@
 package si
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"math/rand"
 	"testing"
 )
 
+// roundResults holds formatting results indexed by rounding mode.
+type roundResults = [roundingModeInvalid]string
+
 func TestFormatAppend(t *testing.T) {
 	var tests = []struct {
 		V     int64
 		BaseU Prefix
 		Prec  int
 		Want  string
+		// Round holds the results of rounding modes which differ from Want, the [RoundHalfUp] result.
+		Round roundResults
 	}{
 		// Augment from Prefixed to No prefix.
 		0: {V: 1000, BaseU: PrefixMilli, Prec: 4, Want: "1"},
@@ -26,31 +34,53 @@ func TestFormatAppend(t *testing.T) {
 		8: {V: 100_000_000, BaseU: PrefixMilli, Prec: 4, Want: "100k"},
 		// Augment Prefix to No Prefix with decimals.
 		9:  {V: 1234, BaseU: PrefixMilli, Prec: 4, Want: "1.234"},
-		10: {V: 12344, BaseU: PrefixMilli, Prec: 4, Want: "12.34"},
-		11: {V: 123444, BaseU: PrefixMilli, Prec: 4, Want: "123.4"},
+		10: {V: 12344, BaseU: PrefixMilli, Prec: 4, Want: "12.34", Round: roundResults{RoundCeil: "12.35", RoundAwayFromZero: "12.35"}},
+		11: {V: 123444, BaseU: PrefixMilli, Prec: 4, Want: "123.4", Round: roundResults{RoundCeil: "123.5", RoundAwayFromZero: "123.5"}},
 		// Augment low to high prefix with decimals.
-		12: {V: 1_234_444, BaseU: PrefixMilli, Prec: 4, Want: "1.234k"},
-		13: {V: 12_344_444, BaseU: PrefixMilli, Prec: 4, Want: "12.34k"},
-		14: {V: 123_444_444, BaseU: PrefixMilli, Prec: 4, Want: "123.4k"},
+		12: {V: 1_234_444, BaseU: PrefixMilli, Prec: 4, Want: "1.234k", Round: roundResults{RoundCeil: "1.235k", RoundAwayFromZero: "1.235k"}},
+		13: {V: 12_344_444, BaseU: PrefixMilli, Prec: 4, Want: "12.34k", Round: roundResults{RoundCeil: "12.35k", RoundAwayFromZero: "12.35k"}},
+		14: {V: 123_444_444, BaseU: PrefixMilli, Prec: 4, Want: "123.4k", Round: roundResults{RoundCeil: "123.5k", RoundAwayFromZero: "123.5k"}},
 		// Augment low to high prefix with decimal chop-off.
-		15: {V: 1_234_567, BaseU: PrefixMilli, Prec: 1, Want: "1k"},
-		16: {V: 12_345_678, BaseU: PrefixMilli, Prec: 2, Want: "12k"},
-		17: {V: 123_456_789, BaseU: PrefixMilli, Prec: 3, Want: "123k"},
+		15: {V: 1_234_567, BaseU: PrefixMilli, Prec: 1, Want: "1k", Round: roundResults{RoundCeil: "2k", RoundAwayFromZero: "2k"}},
+		16: {V: 12_345_678, BaseU: PrefixMilli, Prec: 2, Want: "12k", Round: roundResults{RoundCeil: "13k", RoundAwayFromZero: "13k"}},
+		17: {V: 123_456_789, BaseU: PrefixMilli, Prec: 3, Want: "123k", Round: roundResults{RoundCeil: "124k", RoundAwayFromZero: "124k"}},
 		// Rounding simple.
-		18: {V: 1500, BaseU: PrefixMilli, Prec: 1, Want: "2"},
-		19: {V: 1555, BaseU: PrefixMilli, Prec: 3, Want: "1.56"},
-		20: {V: 1550, BaseU: PrefixMilli, Prec: 2, Want: "1.6"},
+		18: {V: 1500, BaseU: PrefixMilli, Prec: 1, Want: "2", Round: roundResults{RoundTowardZero: "1", RoundFloor: "1"}},
+		19: {V: 1555, BaseU: PrefixMilli, Prec: 3, Want: "1.56", Round: roundResults{RoundTowardZero: "1.55", RoundFloor: "1.55"}},
+		20: {V: 1550, BaseU: PrefixMilli, Prec: 2, Want: "1.6", Round: roundResults{RoundTowardZero: "1.5", RoundFloor: "1.5"}},
 		// Rounding close calls.
 		21: {V: 999, BaseU: PrefixMilli, Prec: 3, Want: "999m"},
 		22: {V: 999_999, BaseU: PrefixMilli, Prec: 6, Want: "999.999"},
 		23: {V: 9_999_999, BaseU: PrefixMilli, Prec: 7, Want: "9.999999k"},
 		// Normal rounding events.
-		24: {V: 12345, BaseU: PrefixMilli, Prec: 4, Want: "12.35"},
-		25: {V: 1500, BaseU: PrefixMilli, Prec: 1, Want: "2"},
+		24: {V: 12345, BaseU: PrefixMilli, Prec: 4, Want: "12.35", Round: roundResults{RoundHalfEven: "12.34", RoundTowardZero: "12.34", RoundFloor: "12.34"}},
+		25: {V: 1500, BaseU: PrefixMilli, Prec: 1, Want: "2", Round: roundResults{RoundTowardZero: "1", RoundFloor: "1"}},
 		// 26: {V: 15, Base: PrefixMilli, Prec: 2, Want: "15m"},
 		// Extraordinary base-crossing rounding events.
-		27: {V: 999_999, BaseU: PrefixMicro, Prec: 2, Want: "1"},
-		28: {V: 999_999, BaseU: PrefixMilli, Prec: 2, Want: "1k"},
+		27: {V: 999_999, BaseU: PrefixMicro, Prec: 2, Want: "1", Round: roundResults{RoundTowardZero: "990m", RoundFloor: "990m"}},
+		28: {V: 999_999, BaseU: PrefixMilli, Prec: 2, Want: "1k", Round: roundResults{RoundTowardZero: "990", RoundFloor: "990"}},
+		// Extended SI prefixes.
+		31: {V: 1234, BaseU: PrefixExa, Prec: 3, Want: "1.23Z", Round: roundResults{RoundCeil: "1.24Z", RoundAwayFromZero: "1.24Z"}},
+		32: {V: 1, BaseU: PrefixQuetta, Prec: 3, Want: "1Q"},
+		33: {V: 999_999, BaseU: PrefixYotta, Prec: 2, Want: "1Q", Round: roundResults{RoundTowardZero: "990R", RoundFloor: "990R"}},
+		34: {V: 1, BaseU: PrefixQuecto, Prec: 3, Want: "1q"},
+		35: {V: 12_345, BaseU: PrefixRonto, Prec: 5, Want: "12.345y"},
+		36: {V: 5, BaseU: PrefixZepto, Prec: 5, Want: "5z"},
+		37: {V: 5_000, BaseU: PrefixZetta, Prec: 5, Want: "5Y"},
+		38: {V: 5_000_000, BaseU: PrefixZetta, Prec: 5, Want: "5R"},
+		// Trailing zeros of non-zero decimal part are printed up to the precision.
+		29: {V: 3300, BaseU: PrefixMilli, Prec: 4, Want: "3.300"},
+		30: {V: 1_020_000, BaseU: PrefixMilli, Prec: 7, Want: "1.020000k"},
+		// Integer digits are rounded to the precision.
+		39: {V: 123_456_789, BaseU: PrefixMilli, Prec: 2, Want: "120k", Round: roundResults{RoundCeil: "130k", RoundAwayFromZero: "130k"}},
+		40: {V: 987_654, BaseU: PrefixNone, Prec: 1, Want: "1M", Round: roundResults{RoundTowardZero: "900k", RoundFloor: "900k"}},
+		// Non-engineering base units.
+		41: {V: 250, BaseU: PrefixCenti, Prec: 3, Want: "2.50"},
+		42: {V: 75, BaseU: PrefixHecto, Prec: 3, Want: "7.5k"},
+		43: {V: 5, BaseU: PrefixDeci, Prec: 3, Want: "500m"},
+		// Values of at most 3 digits are formatted in full.
+		44: {V: 999, BaseU: PrefixMilli, Prec: 1, Want: "999m"},
+		45: {V: 125, BaseU: PrefixNone, Prec: 2, Want: "125"},
 	}
 	s := make([]byte, 24)
 	for i, test := range tests {
@@ -61,10 +91,30 @@ func TestFormatAppend(t *testing.T) {
 		if string(s) != test.Want {
 			t.Errorf("case %d: want %s, got %s", i, test.Want, s)
 		}
-		// Negative equivalent
-		s = AppendFixed(s[:0], -test.V, test.BaseU, 'f', test.Prec)
-		if s[0] != '-' || string(s[1:]) != test.Want {
-			t.Errorf("case %d: want -%s, got %s", i, test.Want, s)
+		for mode := RoundingMode(0); mode < roundingModeInvalid; mode++ {
+			want := test.Round[mode]
+			if want == "" {
+				want = test.Want
+			}
+			s = AppendFixedRound(s[:0], test.V, test.BaseU, 'f', test.Prec, mode)
+			if string(s) != want {
+				t.Errorf("case %d %s: want %s, got %s", i, mode, want, s)
+			}
+			// Negative equivalent rounds as the positive value with floor and ceil swapped.
+			negMode := mode
+			if mode == RoundFloor {
+				negMode = RoundCeil
+			} else if mode == RoundCeil {
+				negMode = RoundFloor
+			}
+			want = test.Round[negMode]
+			if want == "" {
+				want = test.Want
+			}
+			s = AppendFixedRound(s[:0], -test.V, test.BaseU, 'f', test.Prec, mode)
+			if s[0] != '-' || string(s[1:]) != want {
+				t.Errorf("case %d %s: want -%s, got %s", i, mode, want, s)
+			}
 		}
 	}
 }
@@ -152,6 +202,9 @@ func TestIlog10(t *testing.T) {
 		{v: 100, want: 2},
 		{v: 999, want: 2},
 		{v: 1000, want: 3},
+		{v: 999_999_999_999_999_999, want: 17},
+		{v: 1_000_000_000_000_000_000, want: 18},
+		{v: math.MaxInt64, want: 18},
 	}
 	for i, test := range tests {
 		got := ilog10(test.v)
@@ -261,6 +314,29 @@ func TestParseFixed(t *testing.T) {
 		33: {S: "3P", BaseU: PrefixKilo, Want: 3_000_000_000_000},
 		34: {S: "1E", BaseU: PrefixKilo, Want: 1_000_000_000_000_000},
 		35: {S: "9E", BaseU: PrefixMega, Want: 9_000_000_000_000},
+		// Extended SI prefixes.
+		36: {S: "1Z", BaseU: PrefixExa, Want: 1_000},
+		37: {S: "2.5Y", BaseU: PrefixZetta, Want: 2_500},
+		38: {S: "1R", BaseU: PrefixPeta, Want: 1_000_000_000_000},
+		39: {S: "1Q", BaseU: PrefixPeta, Want: 1_000_000_000_000_000},
+		40: {S: "7z", BaseU: PrefixZepto, Want: 7},
+		41: {S: "7y", BaseU: PrefixQuecto, Want: 7_000_000},
+		42: {S: "7.25r", BaseU: PrefixQuecto, Want: 7_250},
+		43: {S: "7q", BaseU: PrefixQuecto, Want: 7},
+		// Values which round to zero in base units.
+		44: {S: "7q", BaseU: PrefixMilli, Want: 0},
+		45: {S: "1e-40", BaseU: PrefixNone, Want: 0},
+		46: {S: "0", BaseU: PrefixNone, Want: 0},
+		47: {S: "0.000000000000000000001234k", BaseU: PrefixQuecto, Want: 1_234_000_000_000},
+		// Non-engineering prefixes.
+		48: {S: "2.5c", BaseU: PrefixMilli, Want: 25},
+		49: {S: "750h", BaseU: PrefixNone, Want: 75_000},
+		50: {S: "3d", BaseU: PrefixMilli, Want: 300},
+		51: {S: "3da", BaseU: PrefixNone, Want: 30},
+		52: {S: "1.5h", BaseU: PrefixDeca, Want: 15},
+		// Exponents beyond the int range.
+		53: {S: "1e-9223372036854775808", BaseU: PrefixQuecto, Want: 0},
+		54: {S: "0e9223372036854775807", BaseU: PrefixQuecto, Want: 0},
 	}
 	for i, test := range tests {
 		if test.S == "" {
@@ -318,27 +394,83 @@ func TestAppendFixedErrors(t *testing.T) {
 		V     int64
 		BaseU Prefix
 		Prec  int
+		Fmt   byte
+		Err   error
 	}{
 		// Invalid prec.
-		0: {V: 1234, Prec: -1},
-		1: {V: 1234, Prec: 0},
-		2: {V: 1234, Prec: 22},
+		0: {V: 1234, Prec: -1, Err: ErrPrecisionTooSmall},
+		1: {V: 1234, Prec: 0, Err: ErrPrecisionTooSmall},
+		2: {V: 1234, Prec: 22, Err: ErrPrecisionTooLarge},
 		// Invalid Prefix.
-		3: {V: 1234, Prec: 1, BaseU: 1},
-		4: {V: 1234, Prec: 1, BaseU: PrefixExa + 3},
-		5: {V: 1234, Prec: 1, BaseU: PrefixAtto - 3},
-		// Exceed base units upwards (beyond PrefixExa).
-		6: {V: 1234, Prec: 3, BaseU: PrefixExa},
-		7: {V: 1234567, Prec: 1, BaseU: PrefixPeta},
-		8: {V: 1234567, Prec: 3, BaseU: PrefixPeta},
+		3: {V: 1234, Prec: 1, BaseU: 4, Err: ErrInvalidBase},
+		4: {V: 1234, Prec: 1, BaseU: PrefixQuetta + 3, Err: ErrInvalidBase},
+		5: {V: 1234, Prec: 1, BaseU: PrefixQuecto - 3, Err: ErrInvalidBase},
+		// Exceed base units upwards (beyond PrefixQuetta).
+		6: {V: 1234, Prec: 3, BaseU: PrefixQuetta, Err: ErrUnrepresentable},
+		7: {V: 1234567, Prec: 1, BaseU: PrefixRonna, Err: ErrUnrepresentable},
+		8: {V: 1234567, Prec: 3, BaseU: PrefixRonna, Err: ErrUnrepresentable},
+		// Invalid fmt.
+		9: {V: 1234, Prec: 3, Fmt: 'x', Err: ErrInvalidFormat},
 	}
 	var buf [64]byte
 	for i, test := range tests {
-		s := AppendFixed(buf[:0], test.V, test.BaseU, 'f', test.Prec)
+		if test.Fmt == 0 {
+			test.Fmt = 'f'
+		}
+		s := AppendFixed(buf[:0], test.V, test.BaseU, test.Fmt, test.Prec)
 		if s[0] != '<' {
 			t.Errorf("case %d: expected error, got %q", i, s)
 		}
 		s[0] = '0' // reset to avoid flukes.
+		s, err := AppendFixedErr(buf[:1], test.V, test.BaseU, test.Fmt, test.Prec)
+		if err != test.Err {
+			t.Errorf("case %d: want error %v, got %v", i, test.Err, err)
+		} else if len(s) != 1 {
+			t.Errorf("case %d: buffer modified on error: %q", i, s)
+		}
+	}
+	s, err := AppendFixedErr(buf[:0], 1234, PrefixMilli, 'f', 3)
+	if err != nil || string(s) != "1.23" {
+		t.Errorf("unexpected result %q, %v", s, err)
+	}
+}
+
+func TestParseErrorOffset(t *testing.T) {
+	var tests = []struct {
+		S      string
+		Kind   ParseErrorKind
+		Offset int
+	}{
+		0:  {S: "1..234", Kind: ParseErrDotDot, Offset: 2},
+		1:  {S: "1-234", Kind: ParseErrNaN, Offset: 1},
+		2:  {S: "--1", Kind: ParseErrMinusMinus, Offset: 1},
+		3:  {S: "-+1", Kind: ParseErrPlusMinus, Offset: 1},
+		4:  {S: "3.3x", Kind: ParseErrUnknownPrefix, Offset: 3},
+		5:  {S: "2e-", Kind: ParseErrNaN, Offset: 3},
+		6:  {S: "2e+x", Kind: ParseErrNaN, Offset: 3},
+		7:  {S: "", Kind: ParseErrNaN, Offset: 0},
+		8:  {S: "-k", Kind: ParseErrNaN, Offset: 1},
+		9:  {S: "12345678901234567890", Kind: ParseErrOverflow, Offset: 19},
+		10: {S: "10Q", Kind: ParseErrOverflow, Offset: 0},
+	}
+	for i, test := range tests {
+		_, _, err := ParseFixed(test.S, PrefixNone)
+		pe, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("case %d: expected *ParseError, got %T %v", i, err, err)
+			continue
+		}
+		if pe.Kind != test.Kind || pe.Offset != test.Offset || pe.Input != test.S {
+			t.Errorf("case %d: want %q at %d in %q, got %q at %d in %q", i, test.Kind, test.Offset, test.S, pe.Kind, pe.Offset, pe.Input)
+		}
+	}
+	_, _, err := ParseFixed("3.3x", PrefixNone)
+	if err.Error() != `unknown SI prefix at offset 3 in "3.3x"` {
+		t.Error("unexpected error message", err)
+	}
+	_, _, err = siDimFormatter.ParseDimension("m·s^x")
+	if pe, ok := err.(*ParseError); !ok || pe.Kind != ParseErrBadExponent || pe.Offset != 5 {
+		t.Errorf("unexpected dimension parse error %v", err)
 	}
 }
 
@@ -346,41 +478,57 @@ func TestParseFixedErrors(t *testing.T) {
 	var tests = []struct {
 		S     string
 		BaseU Prefix
+		Err   error
 	}{
 		// Bad dots.
-		0: {S: "1..234"},
-		1: {S: ".1234."},
-		2: {S: "1.2.34"},
+		0: {S: "1..234", Err: ErrDotDot},
+		1: {S: ".1234.", Err: ErrDotDot},
+		2: {S: "1.2.34", Err: ErrDotDot},
 		// Bad Minus.
-		3: {S: "--1234"},
-		4: {S: "1-234"},
-		5: {S: "-1234-"},
+		3: {S: "--1234", Err: ErrMinusMinus},
+		4: {S: "1-234", Err: ErrNaN},
+		5: {S: "-1234-", Err: ErrMinusMinus},
 		// Bad Plus.
-		6: {S: "1+234"},
-		7: {S: "++1234"},
-		8: {S: "+1+234"},
+		6: {S: "1+234", Err: ErrNaN},
+		7: {S: "++1234", Err: ErrPlusPlus},
+		8: {S: "+1+234", Err: ErrPlusPlus},
 		// Bad Plus/minus
-		9:  {S: "-+1234"},
-		10: {S: "+-1234"},
-		11: {S: "+1234-"},
+		9:  {S: "-+1234", Err: ErrPlusMinus},
+		10: {S: "+-1234", Err: ErrPlusPlus},
+		11: {S: "+1234-", Err: ErrPlusPlus},
 		// Digit overflow.
-		12: {S: "12345678901234567890"},  // 20 digits.
-		13: {S: "1.2345678901234567890"}, // 20 digits with dot.
-		14: {S: "12345678901234567890."},
+		12: {S: "12345678901234567890", Err: ErrOverflowsInt64},  // 20 digits.
+		13: {S: "1.2345678901234567890", Err: ErrOverflowsInt64}, // 20 digits with dot.
+		14: {S: "12345678901234567890.", Err: ErrOverflowsInt64},
 		// Base unit overflow.
-		15: {S: "12345678901234567", BaseU: PrefixMilli},
-		16: {S: "12345678901234567.000", BaseU: PrefixMilli},
-		17: {S: "12345678901234", BaseU: PrefixMicro},
+		15: {S: "12345678901234567", BaseU: PrefixMilli, Err: ErrOverflowsInt64},
+		16: {S: "12345678901234567.000", BaseU: PrefixMilli, Err: ErrOverflowsInt64},
+		17: {S: "12345678901234", BaseU: PrefixMicro, Err: ErrOverflowsInt64},
+		27: {S: "1Q", BaseU: PrefixQuecto, Err: ErrOverflowsInt64},
+		28: {S: "10Q", BaseU: PrefixNone, Err: ErrOverflowsInt64},
 		// Bad exponent notation.
-		18: {S: "2e"},    // Lowercase 'e' at end is unknown prefix (not Exa).
-		19: {S: "2e-"},   // Missing exponent digits after sign.
-		20: {S: "2e+"},   // Missing exponent digits after sign.
-		21: {S: "2E-"},   // Missing exponent digits after sign (uppercase).
-		22: {S: "2E+"},   // Missing exponent digits after sign (uppercase).
-		23: {S: "2e--3"}, // Double negative in exponent.
-		24: {S: "2e++3"}, // Double plus in exponent.
-		25: {S: "2e+-3"}, // Plus and minus in exponent.
-		26: {S: "2e-+3"}, // Minus and plus in exponent.
+		18: {S: "2e", Err: ErrUnknownPrefix}, // Lowercase 'e' at end is unknown prefix (not Exa).
+		19: {S: "2e-", Err: ErrNaN},          // Missing exponent digits after sign.
+		20: {S: "2e+", Err: ErrNaN},          // Missing exponent digits after sign.
+		21: {S: "2E-", Err: ErrNaN},          // Missing exponent digits after sign (uppercase).
+		22: {S: "2E+", Err: ErrNaN},          // Missing exponent digits after sign (uppercase).
+		23: {S: "2e--3", Err: ErrNaN},        // Double negative in exponent.
+		24: {S: "2e++3", Err: ErrNaN},        // Double plus in exponent.
+		25: {S: "2e+-3", Err: ErrNaN},        // Plus and minus in exponent.
+		26: {S: "2e-+3", Err: ErrNaN},        // Minus and plus in exponent.
+		// Negative overflow.
+		29: {S: "-12345678901234567890", Err: ErrOverflowsInt64Negative},
+		30: {S: "-10Q", BaseU: PrefixNone, Err: ErrOverflowsInt64Negative},
+		// Exponent overflow.
+		31: {S: "1e99999999999999999999", Err: ErrOverflowsInt64},
+		37: {S: "1e9223372036854775807", BaseU: PrefixQuecto, Err: ErrOverflowsInt64},
+		38: {S: "-1e9223372036854775807", BaseU: PrefixQuecto, Err: ErrOverflowsInt64Negative},
+		// No number or unknown prefix.
+		32: {S: "", Err: ErrNaN},
+		33: {S: "k", Err: ErrNaN},
+		34: {S: "-.", Err: ErrNaN},
+		35: {S: "5x", Err: ErrUnknownPrefix},
+		36: {S: "5", BaseU: 4, Err: ErrInvalidPrefix},
 	}
 	for i, test := range tests {
 		v, n, err := ParseFixed(test.S, test.BaseU)
@@ -392,6 +540,48 @@ func TestParseFixedErrors(t *testing.T) {
 		} else if v != 0 {
 			t.Errorf("case %d: expected zero output value, got %d from %q", i, v, test.S)
 		}
+		if !errors.Is(err, test.Err) {
+			t.Errorf("case %d: want error %v, got %v from %q", i, test.Err, err, test.S)
+		}
+		if !errors.Is(fmt.Errorf("wrapped: %w", err), test.Err) {
+			t.Errorf("case %d: wrapped error does not match %v", i, test.Err)
+		}
+	}
+}
+
+func TestParseErrorIs(t *testing.T) {
+	_, _, err := ParseFixed("3.3x", PrefixNone)
+	if errors.Is(err, ErrNaN) || errors.Is(err, ErrOverflowsInt64) {
+		t.Error("error matched sentinel of different kind")
+	}
+	located := &ParseError{Kind: ParseErrUnknownPrefix, Offset: 3, Input: "3.3x"}
+	if !errors.Is(err, located) {
+		t.Error("error does not match equal located error")
+	}
+	located.Offset = 2
+	if errors.Is(err, located) {
+		t.Error("error matched located error with different offset")
+	}
+	var pe *ParseError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &pe) || pe.Offset != 3 {
+		t.Error("errors.As failed to find ParseError")
+	}
+}
+
+func TestNewDimensionErrors(t *testing.T) {
+	for i := 0; i < 7; i++ {
+		for _, exp := range []int{-128, 128, 1000} {
+			var exps [7]int
+			exps[i] = exp
+			_, err := NewDimension(exps[0], exps[1], exps[2], exps[3], exps[4], exps[5], exps[6])
+			if !errors.Is(err, ErrDimOOB) {
+				t.Errorf("dim %d exp %d: want ErrDimOOB, got %v", i, exp, err)
+			}
+		}
+	}
+	_, err := NewDimension(127, -127, 0, 0, 0, 0, 0)
+	if err != nil {
+		t.Error(err)
 	}
 }
 
@@ -449,3 +639,164 @@ func newdim(dims []int) Dimension {
 	}
 	return d
 }
+
+func TestParseDimension(t *testing.T) {
+	si, _ := NewDimensionFormatter(DefaultDimensionFormatterConfig())
+	var tests = []struct {
+		DF   *DimensionFormatter
+		S    string
+		Want [7]int
+		N    int // Bytes read, if zero expects all of S read.
+	}{
+		0: {DF: abstractDimFormatter, S: "LM²T⁻³", Want: [7]int{1, 2, -3, 0, 0, 0, 0}},
+		1: {DF: si, S: "m·kg²·s⁻³", Want: [7]int{1, 2, -3, 0, 0, 0, 0}},
+		2: {DF: si, S: "m*kg^2*s^-3", Want: [7]int{1, 2, -3, 0, 0, 0, 0}},
+		3: {DF: si, S: "m·kg/s³", Want: [7]int{1, 1, -3, 0, 0, 0, 0}},
+		4: {DF: si, S: "mol·m", Want: [7]int{1, 0, 0, 0, 0, 0, 1}},
+		5: {DF: si, S: "m·m⁻¹", Want: [7]int{}},
+		6: {DF: abstractDimFormatter, S: "L·I⁻¹²⁷", Want: [7]int{1, 0, 0, 0, -127, 0, 0}},
+		7: {DF: si, S: "", Want: [7]int{}},
+		// Partial reads.
+		8:  {DF: si, S: "ms", Want: [7]int{1, 0, 0, 0, 0, 0, 0}, N: 1},
+		9:  {DF: si, S: "m·", Want: [7]int{1, 0, 0, 0, 0, 0, 0}, N: 1},
+		10: {DF: si, S: "A·V", Want: [7]int{0, 0, 0, 0, 1, 0, 0}, N: 1},
+		11: {DF: abstractDimFormatter, S: "LMx", Want: [7]int{1, 1, 0, 0, 0, 0, 0}, N: 2},
+		12: {DF: si, S: "x", Want: [7]int{}, N: -1},
+	}
+	for i, test := range tests {
+		dim, n, err := test.DF.ParseDimension(test.S)
+		if err != nil {
+			t.Errorf("case %d: %s", i, err)
+			continue
+		}
+		wantN := test.N
+		if wantN == 0 {
+			wantN = len(test.S)
+		} else if wantN < 0 {
+			wantN = 0
+		}
+		if n != wantN {
+			t.Errorf("case %d: bytes read mismatch, got %d want %d", i, n, wantN)
+		}
+		if dim.Exponents() != test.Want {
+			t.Errorf("case %d: want %v, got %v from %q", i, test.Want, dim.Exponents(), test.S)
+		}
+	}
+	for i, s := range []string{"m^", "m^x", "m⁻", "m^-", "m^128", "m¹²⁸", "m^127·m"} {
+		_, n, err := si.ParseDimension(s)
+		if err == nil {
+			t.Errorf("error case %d: expected error from %q", i, s)
+		} else if n != 0 {
+			t.Errorf("error case %d: expected no bytes read on error, got %d", i, n)
+		}
+	}
+}
+
+func TestFormatParseDimensionLoop(t *testing.T) {
+	si, _ := NewDimensionFormatter(DefaultDimensionFormatterConfig())
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		var exps [7]int
+		for j := range exps {
+			if rng.Intn(3) != 0 {
+				exps[j] = rng.Intn(2*maxunit+1) - maxunit
+			}
+		}
+		d := newdim(exps[:])
+		for _, df := range []*DimensionFormatter{si, abstractDimFormatter} {
+			s := df.StringDim(d)
+			got, n, err := df.ParseDimension(s)
+			if err != nil {
+				t.Fatalf("%s: %q", err, s)
+			} else if n != len(s) {
+				t.Fatalf("mismatch number of bytes read got %d, want %d for %q", n, len(s), s)
+			}
+			if got != d {
+				t.Fatalf("format-parse loop failed for got %v, want %v for %q", got.Exponents(), exps, s)
+			}
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	var tests = []struct {
+		P    Prefix
+		Char rune
+		Exp  int
+	}{
+		{P: PrefixQuecto, Char: 'q', Exp: -30},
+		{P: PrefixRonto, Char: 'r', Exp: -27},
+		{P: PrefixYocto, Char: 'y', Exp: -24},
+		{P: PrefixZepto, Char: 'z', Exp: -21},
+		{P: PrefixAtto, Char: 'a', Exp: -18},
+		{P: PrefixFemto, Char: 'f', Exp: -15},
+		{P: PrefixPico, Char: 'p', Exp: -12},
+		{P: PrefixNano, Char: 'n', Exp: -9},
+		{P: PrefixMicro, Char: 'μ', Exp: -6},
+		{P: PrefixMilli, Char: 'm', Exp: -3},
+		{P: PrefixKilo, Char: 'k', Exp: 3},
+		{P: PrefixMega, Char: 'M', Exp: 6},
+		{P: PrefixGiga, Char: 'G', Exp: 9},
+		{P: PrefixTera, Char: 'T', Exp: 12},
+		{P: PrefixPeta, Char: 'P', Exp: 15},
+		{P: PrefixExa, Char: 'E', Exp: 18},
+		{P: PrefixZetta, Char: 'Z', Exp: 21},
+		{P: PrefixYotta, Char: 'Y', Exp: 24},
+		{P: PrefixRonna, Char: 'R', Exp: 27},
+		{P: PrefixQuetta, Char: 'Q', Exp: 30},
+	}
+	for _, test := range tests {
+		if !test.P.IsValid() {
+			t.Errorf("%d: expected valid prefix", test.Exp)
+		}
+		if test.P.Character() != test.Char || test.P.String() != string(test.Char) {
+			t.Errorf("%d: want %q, got %q and %q", test.Exp, test.Char, test.P.Character(), test.P.String())
+		}
+		if test.P.Exponent() != test.Exp {
+			t.Errorf("%d: got exponent %d", test.Exp, test.P.Exponent())
+		}
+		got, err := ExponentToPrefix(test.Exp)
+		if err != nil || got != test.P {
+			t.Errorf("%d: ExponentToPrefix got %v, %v", test.Exp, got, err)
+		}
+		got, err = RuneToPrefix(test.Char)
+		if err != nil || got != test.P {
+			t.Errorf("%d: RuneToPrefix got %v, %v", test.Exp, got, err)
+		}
+	}
+	for _, test := range []struct {
+		Exp int
+		Err error
+	}{
+		{Exp: -33, Err: ErrPrefixTooSmall},
+		{Exp: 33, Err: ErrPrefixTooLarge},
+		{Exp: 4, Err: ErrPrefixNotMod3},
+		{Exp: -4, Err: ErrPrefixNotMod3},
+		{Exp: 100, Err: ErrPrefixNotMod3},
+	} {
+		_, err := ExponentToPrefix(test.Exp)
+		if !errors.Is(fmt.Errorf("wrapped: %w", err), test.Err) {
+			t.Errorf("%d: want error %v, got %v", test.Exp, test.Err, err)
+		}
+		if Prefix(test.Exp).IsValid() {
+			t.Errorf("%d: expected invalid prefix", test.Exp)
+		}
+	}
+}
+
+// Peta was missing from the prefix symbol table, so it was printed as "E" and Exa was printed as invalid.
+func TestPrefixPetaExaRegression(t *testing.T) {
+	if PrefixPeta.String() != "P" || PrefixExa.String() != "E" {
+		t.Fatalf("want P and E, got %q and %q", PrefixPeta.String(), PrefixExa.String())
+	}
+	for _, want := range []string{"1.5P", "2E"} {
+		v, _, err := ParseFixed(want, PrefixTera)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := string(AppendFixed(nil, v, PrefixTera, 'f', 2))
+		if got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	}
+}
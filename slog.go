@@ -0,0 +1,26 @@
+//go:build go1.21
+
+package si
+
+import "log/slog"
+
+// LogValue implements [slog.LogValuer]. The quantity is logged as a group holding the human readable
+// text returned by [Quantity.String] and the lossless fixed-point value, base prefix exponent and dimension,
+// with the same keys as the [JSONLossless] form. The dimension is omitted for dimensionless quantities, i.e:
+//
+//	current.text=3.3mA current.value=3300 current.prefix=-6 current.dim=I
+func (q Quantity) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 3, 4)
+	attrs[0] = slog.String("text", q.String())
+	attrs[1] = slog.Int64("value", q.value)
+	attrs[2] = slog.Int("prefix", q.base.Exponent())
+	if !q.dim.IsDimensionless() {
+		attrs = append(attrs, slog.Any("dim", q.dim))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements [slog.LogValuer]. The dimension is logged in the abstract form of [Dimension.MarshalText].
+func (d Dimension) LogValue() slog.Value {
+	return slog.StringValue(d.String())
+}
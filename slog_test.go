@@ -0,0 +1,46 @@
+//go:build go1.21
+
+package si
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestQuantityLogValue(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("read", "current", Quantity{value: 3300, base: PrefixMicro, dim: current}, "gain", Quantity{value: 25, base: PrefixDeci})
+	const want = "level=INFO msg=read current.text=3.3mA current.value=3300 current.prefix=-6 current.dim=I gain.text=2.5 gain.value=25 gain.prefix=-1\n"
+	if buf.String() != want {
+		t.Errorf("want %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("read", "current", Quantity{value: -5, base: PrefixKilo, dim: current}, "dim", newdim([]int{2, 1, -3, 0, 0, 0, 0}))
+	var got struct {
+		Current map[string]any
+		Dim     string
+	}
+	err := json.Unmarshal(buf.Bytes(), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Current["text"] != "-5kA" || got.Current["value"] != -5.0 || got.Current["prefix"] != 3.0 || got.Current["dim"] != "I" {
+		t.Error("unexpected JSON log", buf.String())
+	}
+	if got.Dim != "L²MT⁻³" {
+		t.Error("unexpected dimension", got.Dim)
+	}
+}
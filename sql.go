@@ -0,0 +1,134 @@
+package si
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var errInexactBase = errors.New("quantity not exactly representable in declared base units")
+
+// Value implements [driver.Valuer]. The quantity is stored as the string of its
+// [JSONLossless] form, i.e: `{"value":3300,"prefix":-3,"dim":"I"}`, which preserves
+// the value, base prefix and dimension. Use [IntQuantity] to store an integer instead.
+func (q Quantity) Value() (driver.Value, error) {
+	b, err := LosslessQuantity{q}.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements [sql.Scanner]. It accepts the strings produced by [Quantity.Value] and human
+// readable strings as accepted by [Quantity.UnmarshalJSON], i.e: "4.7kΩ". Integers are read as
+// dimensionless quantities. Floating point values are rejected to avoid losing precision.
+// A NULL value sets q to the zero quantity.
+func (q *Quantity) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*q = Quantity{}
+		return nil
+	case int64:
+		*q = Quantity{value: v}
+		return nil
+	case string:
+		return q.scanText(v)
+	case []byte:
+		return q.scanText(string(v))
+	}
+	return fmt.Errorf("cannot scan %T into Quantity", src)
+}
+
+func (q *Quantity) scanText(s string) error {
+	if len(s) > 0 && s[0] == '{' {
+		return q.UnmarshalJSON([]byte(s))
+	}
+	nq, err := parseQuantityText(s)
+	if err != nil {
+		return err
+	}
+	*q = nq
+	return nil
+}
+
+// IntQuantity is a [Quantity] stored in databases as an integer in StoreBase units.
+// The dimension is not stored: Scan keeps the dimension of the quantity being scanned into,
+// so it should be set beforehand, i.e:
+//
+//	current, _ := si.NewDimension(0, 0, 0, 0, 1, 0, 0)
+//	q := si.IntQuantity{StoreBase: si.PrefixMilli}
+//	q.Quantity, _ = si.NewQuantity(0, si.PrefixMilli, current)
+//	err := row.Scan(&q)
+type IntQuantity struct {
+	Quantity
+	// StoreBase is the declared base prefix of the stored integer. It is not named Base
+	// so that the Base method of Quantity is not shadowed.
+	StoreBase Prefix
+}
+
+// Value implements [driver.Valuer]. It returns the fixed-point value of q in StoreBase units as an int64 and
+// an error if it can not be represented exactly.
+func (q IntQuantity) Value() (driver.Value, error) {
+	if !q.StoreBase.IsValid() {
+		return nil, ErrInvalidPrefix
+	}
+	v, overflow := rescale(q.value, q.base, q.StoreBase, RoundHalfUp)
+	if overflow {
+		return nil, ErrOverflow
+	}
+	if back, _ := rescale(v, q.StoreBase, q.base, RoundHalfUp); back != q.value {
+		return nil, errInexactBase
+	}
+	return v, nil
+}
+
+// Scan implements [sql.Scanner]. It accepts integers and their decimal string representation,
+// which are read in StoreBase units. A NULL value sets the fixed-point value to zero.
+func (q *IntQuantity) Scan(src any) error {
+	if !q.StoreBase.IsValid() {
+		return ErrInvalidPrefix
+	}
+	var v int64
+	var err error
+	switch src := src.(type) {
+	case nil:
+	case int64:
+		v = src
+	case string:
+		v, err = strconv.ParseInt(src, 10, 64)
+	case []byte:
+		v, err = strconv.ParseInt(string(src), 10, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into IntQuantity", src)
+	}
+	if err != nil {
+		return err
+	}
+	q.Quantity = Quantity{value: v, base: q.StoreBase, dim: q.dim}
+	return nil
+}
+
+// Value implements [driver.Valuer]. The dimension is stored as the string returned by [Dimension.MarshalText].
+func (d Dimension) Value() (driver.Value, error) {
+	b, err := d.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements [sql.Scanner]. It accepts strings as in [Dimension.UnmarshalText].
+// A NULL value sets d to dimensionless.
+func (d *Dimension) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Dimension{}
+		return nil
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	}
+	return fmt.Errorf("cannot scan %T into Dimension", src)
+}
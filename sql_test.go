@@ -0,0 +1,115 @@
+package si
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = Quantity{}
+	_ sql.Scanner   = (*Quantity)(nil)
+	_ driver.Valuer = IntQuantity{}
+	_ sql.Scanner   = (*IntQuantity)(nil)
+	_ driver.Valuer = Dimension{}
+	_ sql.Scanner   = (*Dimension)(nil)
+)
+
+func TestQuantitySQL(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	rng := []Quantity{
+		{value: 3000, base: PrefixMilli, dim: current},
+		{value: -1, base: PrefixQuecto},
+		{value: 1 << 62, base: PrefixQuetta, dim: newdim([]int{-127, 127, 1, 2, 3, 4, 5})},
+		{},
+	}
+	for i, q := range rng {
+		v, err := q.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Quantity
+		if err = got.Scan(v); err != nil || got != q {
+			t.Errorf("case %d: string round trip failed: %v %v", i, got, err)
+		}
+		if err = got.Scan([]byte(v.(string))); err != nil || got != q {
+			t.Errorf("case %d: bytes round trip failed: %v %v", i, got, err)
+		}
+	}
+	if v, _ := rng[0].Value(); v != `{"value":3000,"prefix":-3,"dim":"I"}` {
+		t.Error("unexpected value", v)
+	}
+	var got Quantity
+	if err := got.Scan("4.7kΩ"); err != nil || got.String() != "4.7kΩ" {
+		t.Error("human scan failed", got, err)
+	}
+	if err := got.Scan(int64(42)); err != nil || got != (Quantity{value: 42}) {
+		t.Error("integer scan failed", got, err)
+	}
+	if err := got.Scan(nil); err != nil || got != (Quantity{}) {
+		t.Error("NULL scan failed", got, err)
+	}
+	for i, src := range []any{1.5, "4.7x", true, `{"value":1,"prefix":4}`} {
+		if err := got.Scan(src); err == nil {
+			t.Errorf("error case %d: expected error scanning %v", i, src)
+		}
+	}
+}
+
+func TestIntQuantitySQL(t *testing.T) {
+	current := newdim([]int{0, 0, 0, 0, 1, 0, 0})
+	q := IntQuantity{Quantity: Quantity{value: 3, base: PrefixNone, dim: current}, StoreBase: PrefixMilli}
+	v, err := q.Value()
+	if err != nil || v != int64(3000) {
+		t.Fatal("unexpected value", v, err)
+	}
+	got := IntQuantity{Quantity: Quantity{dim: current}, StoreBase: PrefixMilli}
+	if err = got.Scan(v); err != nil || got.Quantity != (Quantity{value: 3000, base: PrefixMilli, dim: current}) {
+		t.Error("scan failed", got, err)
+	}
+	if got.Base() != PrefixMilli {
+		t.Error("unexpected base", got.Base())
+	}
+	if err = got.Scan("-25"); err != nil || got.Fixed() != -25 || got.Dimension() != current {
+		t.Error("string scan failed", got, err)
+	}
+	if err = got.Scan(nil); err != nil || got.Fixed() != 0 {
+		t.Error("NULL scan failed", got, err)
+	}
+	for i, q := range []IntQuantity{
+		{Quantity: Quantity{value: 1, base: PrefixMicro}, StoreBase: PrefixMilli}, // Inexact.
+		{Quantity: Quantity{value: 1 << 62, base: PrefixNone}, StoreBase: PrefixMilli},
+		{Quantity: Quantity{value: 1}, StoreBase: 4},
+	} {
+		if _, err := q.Value(); err == nil {
+			t.Errorf("error case %d: expected error", i)
+		}
+	}
+	if err = got.Scan(1.5); err == nil {
+		t.Error("expected error scanning float")
+	}
+}
+
+func TestDimensionSQL(t *testing.T) {
+	d := newdim([]int{2, 1, -3, 0, 0, 0, 0})
+	v, err := d.Value()
+	if err != nil || v != "L²MT⁻³" {
+		t.Fatal("unexpected value", v, err)
+	}
+	var got Dimension
+	if err = got.Scan(v); err != nil || got != d {
+		t.Error("scan failed", got, err)
+	}
+	if err = got.Scan([]byte("L^2MT^-3")); err != nil || got != d {
+		t.Error("bytes scan failed", got, err)
+	}
+	if err = got.Scan("W"); err == nil {
+		t.Error("expected error scanning SI unit")
+	}
+	if err = got.Scan(nil); err != nil || got != (Dimension{}) {
+		t.Error("NULL scan failed", got, err)
+	}
+	if err = got.Scan(int64(1)); err == nil {
+		t.Error("expected error scanning integer")
+	}
+}